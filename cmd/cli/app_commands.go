@@ -1,9 +1,10 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"github.com/faelmori/logz"
-	"github.com/faelmori/xtui/types"
+	"github.com/faelmori/xtui/components"
 	. "github.com/faelmori/xtui/wrappers"
 	"github.com/spf13/cobra"
 	"strings"
@@ -48,18 +49,18 @@ func InstallApplicationsCommand() *cobra.Command {
 			}
 
 			// Notification: Starting installation
-			DisplayNotification("Starting installation of applications", "info")
+			components.DisplayInfoNotification("Starting installation of applications")
 
 			err := InstallDependenciesWithUI(args...)
 
 			if err != nil {
 				// Notification: Error during installation
-				DisplayNotification(fmt.Sprintf("Error during installation: %s", err.Error()), "error")
+				components.DisplayErrorNotification(fmt.Sprintf("Error during installation: %s", err.Error()))
 				return err
 			}
 
 			// Notification: Successful installation
-			DisplayNotification("Applications installed successfully", "info")
+			components.DisplayInfoNotification("Applications installed successfully")
 
 			return nil
 		},
@@ -95,40 +96,19 @@ func NavigateAndExecuteCommand(cmd *cobra.Command, args []string) error {
 
 	// Display command selection and flag definition in a form
 	formConfig := createFormConfig(commandName, flags)
-	formResult, err := ShowFormWithNotification(formConfig)
+	formResult, err := components.ShowFormWithNotification(formConfig)
+	if errors.Is(err, components.ErrFormCanceled) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
 	// Set flag values based on form input
-	for key, value := range formResult {
-		if err := cmd.Flags().Set(key, value); err != nil {
-			return err
-		}
+	if err := setFlagsFromForm(flags, formResult); err != nil {
+		return err
 	}
 
 	// Execute the command
 	return cmd.Execute()
 }
-
-func createFormConfig(commandName string, flags *pflag.FlagSet) Config {
-	var formFields []FormField
-
-	flags.VisitAll(func(flag *pflag.Flag) {
-		formFields = append(formFields, InputField{
-			Ph:  flag.Name,
-			Tp:  "text",
-			Val: flag.Value.String(),
-			Req: false,
-			Min: 0,
-			Max: 100,
-			Err: "",
-			Vld: func(value string) error { return nil },
-		})
-	})
-
-	return Config{
-		Title:  fmt.Sprintf("Configure %s Command", commandName),
-		Fields: FormFields{Fields: formFields},
-	}
-}
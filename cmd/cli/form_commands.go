@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/faelmori/xtui/components"
@@ -8,6 +9,7 @@ import (
 	"github.com/faelmori/xtui/wrappers"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"strings"
 	"testing"
 	"time"
 )
@@ -112,32 +114,34 @@ func NavigateAndExecuteFormCommand(cmd *cobra.Command, args []string) error {
 	// Display command selection and flag definition in a form
 	formConfig := createFormConfig(commandName, flags)
 	formResult, err := components.ShowFormWithNotification(formConfig)
+	if errors.Is(err, components.ErrFormCanceled) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
 	// Set flag values based on form input
-	for key, value := range formResult {
-		if err := cmd.Flags().Set(key, value); err != nil {
-			return err
-		}
+	if err := setFlagsFromForm(flags, formResult); err != nil {
+		return err
 	}
 
 	// Execute the command
 	return cmd.Execute()
 }
 
+// createFormConfig builds a form with a field for each flag, named after the flag. List flags are
+// edited as comma separated values.
 func createFormConfig(commandName string, flags *pflag.FlagSet) types.Config {
 	var formFields []types.FormField
 
 	flags.VisitAll(func(flag *pflag.Flag) {
 		formFields = append(formFields, &types.InputField{
+			Nm:  flag.Name,
 			Ph:  flag.Name,
-			Tp:  "text",
-			Val: flag.Value.String(),
+			Tp:  flagFieldType(flag),
+			Val: flagFormValue(flag),
 			Req: false,
-			Min: 0,
-			Max: 100,
 			Err: "",
 			Vld: func(value string) error { return nil },
 		})
@@ -148,3 +152,45 @@ func createFormConfig(commandName string, flags *pflag.FlagSet) types.Config {
 		Fields: types.FormFields{Fields: formFields},
 	}
 }
+
+// setFlagsFromForm sets the flags whose form field was changed. List flags are replaced by the
+// items of the field instead of appending to them.
+func setFlagsFromForm(flags *pflag.FlagSet, result *types.FormResult) error {
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || !result.Has(flag.Name) || result.String(flag.Name) == flagFormValue(flag) {
+			return
+		}
+		if list, ok := flag.Value.(pflag.SliceValue); ok {
+			if err = list.Replace(result.Strings(flag.Name)); err == nil {
+				flag.Changed = true
+			}
+		} else {
+			err = flags.Set(flag.Name, result.String(flag.Name))
+		}
+		if err != nil {
+			err = fmt.Errorf("flag --%s: %w", flag.Name, err)
+		}
+	})
+	return err
+}
+
+// flagFormValue returns the value of the flag as shown in the form.
+func flagFormValue(flag *pflag.Flag) string {
+	if list, ok := flag.Value.(pflag.SliceValue); ok {
+		return strings.Join(list.GetSlice(), ",")
+	}
+	return flag.Value.String()
+}
+
+func flagFieldType(flag *pflag.Flag) types.FieldType {
+	switch flag.Value.Type() {
+	case "bool":
+		return types.FieldBool
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return types.FieldInt
+	case "stringSlice", "stringArray", "intSlice", "boolSlice":
+		return types.FieldList
+	}
+	return types.FieldText
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/faelmori/logz"
 	p "github.com/faelmori/xtui/packages"
-	"github.com/faelmori/xtui/types"
 	"github.com/spf13/cobra"
 	"os"
 	"reflect"
@@ -130,22 +129,24 @@ func appsCmdList() *cobra.Command {
 			nameFlagValue, _ := cmd.Flags().GetStringArray("name")
 			statusFlagValue, _ := cmd.Flags().GetString("status")
 			methodFlagValue, _ := cmd.Flags().GetString("method")
+			viewFlagValue, _ := cmd.Flags().GetString("view")
 			newArgs := []string{strings.Join(nameFlagValue, " "), statusFlagValue, methodFlagValue}
 			args = append(args, newArgs...)
 
 			availableProperties := getAvailableProperties()
 			if len(availableProperties) > 0 {
 				adaptedArgs := adaptArgsToProperties(args, availableProperties)
-				return p.ShowInstalledAppsTable(adaptedArgs...)
+				return p.ShowInstalledAppsTableView(viewFlagValue, adaptedArgs...)
 			}
 
-			return p.ShowInstalledAppsTable(args...)
+			return p.ShowInstalledAppsTableView(viewFlagValue, args...)
 		},
 	}
 
 	cmd.Flags().StringArrayP("name", "n", []string{}, "App name")
 	cmd.Flags().StringP("status", "s", "", "App status")
 	cmd.Flags().StringP("method", "m", "", "App method")
+	cmd.Flags().StringP("view", "v", "", "Saved table view to open (F3 in the table saves views)")
 
 	return cmd
 }
@@ -226,18 +227,3 @@ func InstallDepsHandler(args ...string) error {
 	scriptPath = args[0]
 	return p.InstallApps(scriptPath)
 }
-
-func getAvailableProperties() map[string]string {
-	return map[string]string{
-		"property1": "value1",
-		"property2": "value2",
-	}
-}
-
-func adaptArgsToProperties(args []string, properties map[string]string) []string {
-	adaptedArgs := args
-	for key, value := range properties {
-		adaptedArgs = append(adaptedArgs, fmt.Sprintf("--%s=%s", key, value))
-	}
-	return adaptedArgs
-}
@@ -4,17 +4,27 @@ import (
 	"fmt"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/faelmori/xtui/components"
-	t "github.com/faelmori/xtui/types"
+	"github.com/faelmori/xtui/components/export"
+	"github.com/faelmori/xtui/types"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func ViewsCmdsList() []*cobra.Command {
 	tableCmd := tableViewCmd()
+	streamCmd := tableStreamCmd()
+	diffCmd := tableDiffCmd()
+	treeCmd := treeViewCmd()
 
 	return []*cobra.Command{
 		tableCmd,
+		streamCmd,
+		diffCmd,
+		treeCmd,
 	}
 }
 
@@ -33,10 +43,235 @@ func tableViewCmd() *cobra.Command {
 			return NavigateAndExecuteViewCommand(cmd, args)
 		},
 	}
+	cmd.AddCommand(tableFileCmd())
 
 	return cmd
 }
 
+func tableFileCmd() *cobra.Command {
+	var format, title, view string
+
+	cmd := &cobra.Command{
+		Use:     "view <file>",
+		Aliases: []string{"open", "load"},
+		Args:    cobra.ExactArgs(1),
+		Annotations: GetDescriptions(
+			[]string{
+				"Table view of a data file",
+				"Table view of a CSV, TSV, JSON, NDJSON or YAML file, with nested objects flattened into dotted columns",
+			},
+			false,
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := loadTableFile(args[0], format)
+			if err != nil {
+				return err
+			}
+			// views of a file are stored under its name, so they apply to every copy of it
+			id := "file-" + filepath.Base(args[0])
+			if view != "" {
+				if _, err := components.FindTableView(id, view); err != nil {
+					return err
+				}
+			}
+			if title != "" {
+				data.Title = title
+			}
+			return components.StartTableScreenWithData(data, components.TableOptions{
+				Title:       data.Title,
+				ColumnTypes: data.Types,
+				ID:          id,
+				View:        view,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "", "File format: csv, tsv, json, ndjson or yaml (default: from the file extension)")
+	cmd.Flags().StringVarP(&title, "title", "t", "", "Table title (default: the file name)")
+	cmd.Flags().StringVarP(&view, "view", "v", "", "Saved table view to open (F3 in the table saves views)")
+
+	return cmd
+}
+
+// loadTableFile reads the file with LoadTable, or in the given format when one is set.
+func loadTableFile(path, format string) (*export.Table, error) {
+	if format == "" {
+		return export.LoadTable(path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := export.ReadTable(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	data.Title = filepath.Base(path)
+	return data, nil
+}
+
+func tableStreamCmd() *cobra.Command {
+	var format, title string
+	var headers []string
+	var follow bool
+
+	cmd := &cobra.Command{
+		Use:     "stream",
+		Aliases: []string{"tail", "pipe"},
+		Annotations: GetDescriptions(
+			[]string{
+				"Live table view of the standard input",
+				"Live table view of CSV, TSV or whitespace aligned rows piped into the standard input, such as kubectl get -w or dpkg-query output",
+			},
+			false,
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			columns, rows, err := components.ReadTableStream(os.Stdin, format, headers)
+			if err != nil {
+				return err
+			}
+			return components.StartStreamingTable(columns, rows, components.TableOptions{
+				Title:  title,
+				Follow: follow,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", components.StreamAuto, "Input format: auto, csv, tsv or ws (whitespace aligned)")
+	cmd.Flags().StringSliceVarP(&headers, "headers", "H", nil, "Column headers, when the input has no header line")
+	cmd.Flags().StringVarP(&title, "title", "t", "", "Table title")
+	cmd.Flags().BoolVarP(&follow, "follow", "F", true, "Keep the newest rows in view")
+
+	return cmd
+}
+
+func tableDiffCmd() *cobra.Command {
+	var key, leftName, rightName string
+	var onlyDiff bool
+
+	cmd := &cobra.Command{
+		Use:     "diff <left-file> <right-file>",
+		Aliases: []string{"compare"},
+		Args:    cobra.ExactArgs(2),
+		Annotations: GetDescriptions(
+			[]string{
+				"Side-by-side diff of two data files",
+				"Side-by-side diff of two CSV, TSV, JSON, NDJSON or YAML files, with rows matched by a key column",
+			},
+			false,
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			left, err := export.LoadTable(args[0])
+			if err != nil {
+				return err
+			}
+			right, err := export.LoadTable(args[1])
+			if err != nil {
+				return err
+			}
+			if key == "" && len(left.Headers) > 0 {
+				key = left.Headers[0]
+			}
+			if leftName == "" && rightName == "" && left.Title == right.Title {
+				// files of the same name, such as a/packages.csv and b/packages.csv, are told apart by path
+				leftName, rightName = args[0], args[1]
+			}
+			if leftName == "" {
+				leftName = left.Title
+			}
+			if rightName == "" {
+				rightName = right.Title
+			}
+			return components.StartTableDiff(left, right, key, components.DiffOptions{
+				TableOptions:    components.TableOptions{Title: fmt.Sprintf("%s ↔ %s", left.Title, right.Title)},
+				LeftName:        leftName,
+				RightName:       rightName,
+				OnlyDifferences: onlyDiff,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&key, "key", "k", "", "Column matching the rows of both files (default: the first column)")
+	cmd.Flags().StringVarP(&leftName, "left-name", "l", "", "Label of the left file columns (default: the file name, or its path when both names are equal)")
+	cmd.Flags().StringVarP(&rightName, "right-name", "r", "", "Label of the right file columns (default: the file name, or its path when both names are equal)")
+	cmd.Flags().BoolVarP(&onlyDiff, "only-differences", "d", false, "Hide the rows equal in both files")
+
+	return cmd
+}
+
+func treeViewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "tree [dir]",
+		Aliases: []string{"tree-view", "dirs"},
+		Args:    cobra.MaximumNArgs(1),
+		Annotations: GetDescriptions(
+			[]string{
+				"Tree view of a directory",
+				"Expandable tree view of a directory, reading the entries of each subdirectory when it is expanded",
+			},
+			false,
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			children, err := readDirNodes(dir)
+			if err != nil {
+				return err
+			}
+			root := components.NewTreeNode(dir, "dir", "", "").Add(children...)
+			root.Expanded = true
+			return components.StartTreeTable([]string{"Name", "Type", "Size", "Modified"}, []*components.TreeNode{root}, components.TreeOptions{
+				Title: dir,
+				ColumnTypes: map[string]types.ColumnType{
+					"Size":     types.ColumnBytes,
+					"Modified": types.ColumnTime,
+				},
+				LoadChildren: func(node *components.TreeNode) ([]*components.TreeNode, error) {
+					return readDirNodes(treeNodePath(node))
+				},
+			})
+		},
+	}
+
+	return cmd
+}
+
+// readDirNodes lists the entries of a directory as tree nodes; subdirectories are loaded lazily.
+func readDirNodes(dir string) ([]*components.TreeNode, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*components.TreeNode, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		node := &components.TreeNode{Cells: []string{entry.Name(), "file", types.FormatByteSize(float64(info.Size())), info.ModTime().Format(time.DateTime)}}
+		if entry.IsDir() {
+			node.Cells[1], node.Cells[2] = "dir", ""
+			node.Lazy = true
+		} else if entry.Type()&os.ModeSymlink != 0 {
+			node.Cells[1] = "link"
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// treeNodePath joins the names of the node and its ancestors into a file path.
+func treeNodePath(node *components.TreeNode) string {
+	var parts []string
+	for ; node != nil; node = node.Parent() {
+		parts = append([]string{node.Cells[0]}, parts...)
+	}
+	return filepath.Join(parts...)
+}
+
 func TestTableViewCmd(t *testing.T) {
 	cmd := tableViewCmd()
 	if cmd.Use != "table" {
@@ -55,42 +290,45 @@ func NavigateAndExecuteViewCommand(cmd *cobra.Command, args []string) error {
 	commandName := cmd.Name()
 	flags := cmd.Flags()
 
-	// Display command selection and flag definition in a table view
-	tableConfig := createTableConfig(commandName, flags)
+	// Display the flags in a table whose Value column can be edited
+	data := createFlagsTable(commandName, flags)
 	customStyles := map[string]lipgloss.Color{
 		"Info":    lipgloss.Color("#75FBAB"),
 		"Warning": lipgloss.Color("#FDFF90"),
 		"Error":   lipgloss.Color("#FF7698"),
 		"Debug":   lipgloss.Color("#929292"),
 	}
-	if err := components.StartTableScreen(tableConfig, customStyles); err != nil {
-		return err
+	opts := components.TableOptions{
+		Title:        data.Title,
+		CustomStyles: customStyles,
+		Editors: map[string]components.ColumnEditor{
+			"Value": {Placeholder: "flag value"},
+		},
+		// Set flag values based on the committed table edits
+		OnCommit: func(changes []components.CellChange) error {
+			for _, change := range changes {
+				if err := flags.Set(data.Rows[change.Row][0], change.New); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
 	}
-
-	// Set flag values based on table input
-	for key, value := range tableConfig.Fields {
-		if err := cmd.Flags().Set(key, value.Value()); err != nil {
-			return err
-		}
+	if err := components.StartTableScreenWithData(data, opts); err != nil {
+		return err
 	}
 
 	// Execute the command
 	return cmd.Execute()
 }
 
-func createTableConfig(commandName string, flags *pflag.FlagSet) t.FormConfig {
-	var tableFields []t.Field
-
+func createFlagsTable(commandName string, flags *pflag.FlagSet) *export.Table {
+	data := &export.Table{
+		Title:   fmt.Sprintf("Configure %s Command", commandName),
+		Headers: []string{"Flag", "Value", "Default", "Usage"},
+	}
 	flags.VisitAll(func(flag *pflag.Flag) {
-		tableFields = append(tableFields, t.InputField{
-			Ph:  flag.Name,
-			Tp:  "text",
-			Val: flag.Value.String(),
-		})
+		data.Rows = append(data.Rows, []string{flag.Name, flag.Value.String(), flag.DefValue, flag.Usage})
 	})
-
-	return t.FormConfig{
-		Title:  fmt.Sprintf("Configure %s Command", commandName),
-		Fields: tableFields,
-	}
+	return data
 }
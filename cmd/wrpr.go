@@ -84,7 +84,7 @@ func (m *XTui) Command() *cobra.Command {
 			case "tcp-status":
 				return TcpStatus(args...)
 			case "navigate":
-				return NavigateAndExecuteCommand(cmd, args)
+				return cli.NavigateAndExecuteCommand(cmd, args)
 			}
 
 			return fmt.Errorf("error: %s", opts[0])
@@ -139,12 +139,12 @@ func (m *XTui) Command() *cobra.Command {
 	c.AddCommand(formCmdRoot)
 
 	dataCmdRoot := &cobra.Command{
-		Use:     "forms",
-		Aliases: []string{"frm", "form"},
+		Use:     "data",
+		Aliases: []string{"views", "view"},
 		Annotations: cli.GetDescriptions(
 			[]string{
-				"Terminal forms builder",
-				"Build terminal forms with validation, input types, and much more",
+				"Terminal data views",
+				"Interactive tables for command flags, files and streamed command output",
 			}, false,
 		),
 		RunE: func(cmd *cobra.Command, args []string) error { return cmd.Help() },
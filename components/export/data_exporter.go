@@ -0,0 +1,40 @@
+package export
+
+import (
+	"github.com/faelmori/xtui/types"
+)
+
+// DataExporter implements types.DataExporter on top of a registry.
+type DataExporter struct {
+	registry *Registry
+	data     types.TableDataHandler
+}
+
+// NewDataExporter returns a types.DataExporter for data using the default registry.
+func NewDataExporter(data types.TableDataHandler) *DataExporter {
+	return &DataExporter{registry: Default, data: data}
+}
+
+// NewDataExporterWithRegistry returns a types.DataExporter for data using the given registry.
+func NewDataExporterWithRegistry(registry *Registry, data types.TableDataHandler) *DataExporter {
+	return &DataExporter{registry: registry, data: data}
+}
+
+func (e *DataExporter) ExportToCSV(filename string) error  { return e.ExportTo("csv", filename) }
+func (e *DataExporter) ExportToYAML(filename string) error { return e.ExportTo("yaml", filename) }
+func (e *DataExporter) ExportToJSON(filename string) error { return e.ExportTo("json", filename) }
+func (e *DataExporter) ExportToXML(filename string) error  { return e.ExportTo("xml", filename) }
+func (e *DataExporter) ExportToExcel(filename string) error {
+	return e.ExportTo("xlsx", filename)
+}
+func (e *DataExporter) ExportToPDF(filename string) error { return e.ExportTo("pdf", filename) }
+func (e *DataExporter) ExportToMarkdown(filename string) error {
+	return e.ExportTo("markdown", filename)
+}
+
+// ExportTo writes the data to filename with any registered format.
+func (e *DataExporter) ExportTo(format, filename string) error {
+	return e.registry.ExportToFile(format, filename, e.data)
+}
+
+var _ types.DataExporter = (*DataExporter)(nil)
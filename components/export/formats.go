@@ -0,0 +1,255 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"html"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/faelmori/xtui/types"
+	"gopkg.in/yaml.v2"
+)
+
+// WriteCSV writes a header line followed by the rows as comma separated values.
+func WriteCSV(w io.Writer, data types.TableDataHandler) error {
+	return writeDelimited(w, data, ',')
+}
+
+// WriteTSV writes a header line followed by the rows as tab separated values.
+func WriteTSV(w io.Writer, data types.TableDataHandler) error {
+	return writeDelimited(w, data, '\t')
+}
+
+func writeDelimited(w io.Writer, data types.TableDataHandler, comma rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	headers := data.GetHeaders()
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+	for _, row := range data.GetRows() {
+		record := make([]string, len(headers))
+		for i := range headers {
+			record[i] = cell(row, i)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// orderedRecord is a row that marshals to a JSON object keeping the column order.
+type orderedRecord struct {
+	headers []string
+	row     []string
+}
+
+func (r orderedRecord) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, header := range r.headers {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := marshalString(header)
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshalString(cell(r.row, i))
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+// marshalString encodes s as a JSON string without escaping HTML characters.
+func marshalString(s string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// WriteJSON writes the rows as an array of objects keyed by header, in column order.
+func WriteJSON(w io.Writer, data types.TableDataHandler) error {
+	headers := data.GetHeaders()
+	rows := data.GetRows()
+	records := make([]orderedRecord, len(rows))
+	for i, row := range rows {
+		records[i] = orderedRecord{headers: headers, row: row}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// WriteNDJSON writes one JSON object per line, which suits streaming and line based tools.
+func WriteNDJSON(w io.Writer, data types.TableDataHandler) error {
+	headers := data.GetHeaders()
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, row := range data.GetRows() {
+		if err := encoder.Encode(orderedRecord{headers: headers, row: row}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteYAML writes the rows as a sequence of mappings, in column order.
+func WriteYAML(w io.Writer, data types.TableDataHandler) error {
+	headers := data.GetHeaders()
+	rows := data.GetRows()
+	records := make([]yaml.MapSlice, len(rows))
+	for i, row := range rows {
+		record := make(yaml.MapSlice, len(headers))
+		for j, header := range headers {
+			record[j] = yaml.MapItem{Key: header, Value: cell(row, j)}
+		}
+		records[i] = record
+	}
+	encoder := yaml.NewEncoder(w)
+	if err := encoder.Encode(records); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// WriteXML writes a <rows> document with one <row> element per row and one child element per
+// column. Headers are turned into valid element names, and the original header is kept in the
+// name attribute when it had to be changed.
+func WriteXML(w io.Writer, data types.TableDataHandler) error {
+	headers := data.GetHeaders()
+	names := make([]string, len(headers))
+	for i, header := range headers {
+		names[i] = xmlName(header)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	rowsStart := xml.StartElement{Name: xml.Name{Local: "rows"}}
+	if err := encoder.EncodeToken(rowsStart); err != nil {
+		return err
+	}
+	for _, row := range data.GetRows() {
+		rowStart := xml.StartElement{Name: xml.Name{Local: "row"}}
+		if err := encoder.EncodeToken(rowStart); err != nil {
+			return err
+		}
+		for i, header := range headers {
+			start := xml.StartElement{Name: xml.Name{Local: names[i]}}
+			if names[i] != header {
+				start.Attr = []xml.Attr{{Name: xml.Name{Local: "name"}, Value: header}}
+			}
+			if err := encoder.EncodeElement(cell(row, i), start); err != nil {
+				return err
+			}
+		}
+		if err := encoder.EncodeToken(rowStart.End()); err != nil {
+			return err
+		}
+	}
+	if err := encoder.EncodeToken(rowsStart.End()); err != nil {
+		return err
+	}
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// xmlName turns a header into a valid XML element name.
+func xmlName(header string) string {
+	var b strings.Builder
+	for i, r := range header {
+		valid := unicode.IsLetter(r) || r == '_' || (i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'))
+		if valid {
+			b.WriteRune(r)
+		} else if i == 0 && unicode.IsDigit(r) {
+			b.WriteRune('_')
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	name := b.String()
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		name = "_" + name
+	}
+	return name
+}
+
+// WriteMarkdown writes a GitHub flavoured Markdown table.
+func WriteMarkdown(w io.Writer, data types.TableDataHandler) error {
+	bw := bufio.NewWriter(w)
+	headers := data.GetHeaders()
+	writeLine := func(cells []string) {
+		_, _ = bw.WriteString("|")
+		for _, c := range cells {
+			_, _ = bw.WriteString(" " + markdownEscape(c) + " |")
+		}
+		_, _ = bw.WriteString("\n")
+	}
+	writeLine(headers)
+	separator := make([]string, len(headers))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeLine(separator)
+	for _, row := range data.GetRows() {
+		cells := make([]string, len(headers))
+		for i := range headers {
+			cells[i] = cell(row, i)
+		}
+		writeLine(cells)
+	}
+	return bw.Flush()
+}
+
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// WriteHTML writes a standalone HTML document with the table.
+func WriteHTML(w io.Writer, data types.TableDataHandler) error {
+	bw := bufio.NewWriter(w)
+	headers := data.GetHeaders()
+	_, _ = bw.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	_, _ = bw.WriteString("<style>table{border-collapse:collapse}th,td{border:1px solid #999;padding:2px 6px;text-align:left}</style>\n")
+	_, _ = bw.WriteString("</head>\n<body>\n<table>\n<thead>\n<tr>")
+	for _, header := range headers {
+		_, _ = bw.WriteString("<th>" + html.EscapeString(header) + "</th>")
+	}
+	_, _ = bw.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for _, row := range data.GetRows() {
+		_, _ = bw.WriteString("<tr>")
+		for i := range headers {
+			_, _ = bw.WriteString("<td>" + html.EscapeString(cell(row, i)) + "</td>")
+		}
+		_, _ = bw.WriteString("</tr>\n")
+	}
+	_, _ = bw.WriteString("</tbody>\n</table>\n</body>\n</html>\n")
+	return bw.Flush()
+}
@@ -0,0 +1,412 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/faelmori/xtui/types"
+	"gopkg.in/yaml.v2"
+)
+
+// loadExtensions maps file extensions to the formats read by LoadTable.
+var loadExtensions = map[string]string{
+	"csv":    "csv",
+	"tsv":    "tsv",
+	"tab":    "tsv",
+	"json":   "json",
+	"ndjson": "ndjson",
+	"jsonl":  "ndjson",
+	"yaml":   "yaml",
+	"yml":    "yaml",
+}
+
+// LoadTable reads a CSV, TSV, JSON, NDJSON or YAML file into a table. The format is taken from
+// the file extension, or guessed from the content when the extension is unknown. The file name
+// becomes the table title.
+func LoadTable(path string) (*Table, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	format := loadExtensions[strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))]
+	table, err := ReadTable(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	table.Title = filepath.Base(path)
+	return table, nil
+}
+
+// ReadTable reads a table in the given format: csv, tsv, json, ndjson or yaml. An empty format is
+// guessed from the content.
+//
+// Records of JSON, NDJSON and YAML documents become rows and their keys columns, in the order
+// they first appear. Nested objects are flattened into dotted columns such as `metadata.name`,
+// lists of plain values are joined with commas and other lists are kept as JSON. A document that
+// is an object holding a single list of records, like `{"items": [...]}`, is read as that list.
+// Columns whose values are all numbers are typed as such, so they sort numerically.
+func ReadTable(r io.Reader, format string) (*Table, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = sniffFormat(data)
+	}
+
+	var table *Table
+	switch format {
+	case "csv", "tsv":
+		table, err = readDelimited(data, format)
+	case "json":
+		var doc interface{}
+		if doc, err = decodeJSON(json.NewDecoder(bytes.NewReader(data))); err == nil {
+			table = recordsTable(records(doc))
+		}
+	case "ndjson":
+		table, err = readNDJSON(data)
+	case "yaml":
+		var doc interface{}
+		if doc, err = decodeYAML(data); err == nil {
+			table = recordsTable(records(doc))
+		}
+	default:
+		return nil, fmt.Errorf("unknown table format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	table.Types = inferColumnTypes(table)
+	return table, nil
+}
+
+// sniffFormat guesses the format of a document from its first characters and lines.
+func sniffFormat(data []byte) string {
+	text := strings.TrimSpace(string(data))
+	first, rest, _ := strings.Cut(text, "\n")
+	switch {
+	case strings.HasPrefix(text, "["):
+		return "json"
+	case strings.HasPrefix(text, "{"):
+		if strings.HasPrefix(strings.TrimSpace(rest), "{") && strings.HasSuffix(strings.TrimSpace(first), "}") {
+			return "ndjson"
+		}
+		return "json"
+	case strings.HasPrefix(text, "---") || strings.HasPrefix(text, "- "):
+		return "yaml"
+	case strings.Contains(first, "\t"):
+		return "tsv"
+	case strings.Contains(first, ","):
+		return "csv"
+	case strings.Contains(first, ":"):
+		return "yaml"
+	}
+	return "csv"
+}
+
+func readDelimited(data []byte, format string) (*Table, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	if format == "tsv" {
+		reader.Comma = '\t'
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return NewTable(nil, nil), nil
+	}
+	return NewTable(records[0], records[1:]), nil
+}
+
+func readNDJSON(data []byte) (*Table, error) {
+	var docs []interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		doc, err := decodeJSON(json.NewDecoder(strings.NewReader(text)))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		docs = append(docs, doc)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return recordsTable(docs), nil
+}
+
+// decodeJSON decodes the next JSON value, keeping the key order of objects in yaml.MapSlice
+// values, so JSON and YAML documents are flattened alike.
+func decodeJSON(dec *json.Decoder) (interface{}, error) {
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		var object yaml.MapSlice
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, yaml.MapItem{Key: key, Value: value})
+		}
+		_, err = dec.Token()
+		return object, err
+	case json.Delim('['):
+		list := make([]interface{}, 0)
+		for dec.More() {
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// decodeYAML decodes a YAML document into yaml.MapSlice objects, keeping their key order, as long
+// as the document is an object or a list of objects.
+func decodeYAML(data []byte) (interface{}, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	switch doc.(type) {
+	case map[interface{}]interface{}:
+		var object yaml.MapSlice
+		err := yaml.Unmarshal(data, &object)
+		return object, err
+	case []interface{}:
+		var objects []yaml.MapSlice
+		if err := yaml.Unmarshal(data, &objects); err != nil {
+			// a list that is not only objects; its items are taken as they are
+			return doc, nil
+		}
+		list := make([]interface{}, len(objects))
+		for i, object := range objects {
+			list[i] = object
+		}
+		return list, nil
+	}
+	return doc, nil
+}
+
+// records returns the records of a decoded document: the items of a list, the list of records of
+// an object that holds a single one, or the document itself.
+func records(doc interface{}) []interface{} {
+	switch v := doc.(type) {
+	case []interface{}:
+		return v
+	case yaml.MapSlice:
+		var list []interface{}
+		for _, item := range v {
+			if items, ok := item.Value.([]interface{}); ok {
+				if list != nil {
+					return []interface{}{doc}
+				}
+				list = items
+			}
+		}
+		if list != nil && isRecordList(list) {
+			return list
+		}
+	case nil:
+		return nil
+	}
+	return []interface{}{doc}
+}
+
+func isRecordList(list []interface{}) bool {
+	for _, item := range list {
+		if _, ok := item.(yaml.MapSlice); !ok {
+			return false
+		}
+	}
+	return len(list) > 0
+}
+
+// recordsTable flattens the records into rows. Records that are plain values go to a value column.
+func recordsTable(records []interface{}) *Table {
+	var headers []string
+	index := make(map[string]int)
+	rows := make([][]string, 0, len(records))
+	for _, record := range records {
+		cells := make(map[string]string)
+		var keys []string
+		if object, ok := record.(yaml.MapSlice); ok {
+			flatten("", object, cells, &keys)
+		} else {
+			keys = []string{"value"}
+			cells["value"] = scalarString(record)
+		}
+		for _, key := range keys {
+			if _, ok := index[key]; !ok {
+				index[key] = len(headers)
+				headers = append(headers, key)
+			}
+		}
+		row := make([]string, len(headers))
+		for key, value := range cells {
+			row[index[key]] = value
+		}
+		rows = append(rows, row)
+	}
+	// rows read before a column first appeared are shorter; pad them so every row is complete
+	for i, row := range rows {
+		if len(row) < len(headers) {
+			rows[i] = append(row, make([]string, len(headers)-len(row))...)
+		}
+	}
+	return NewTable(headers, rows)
+}
+
+// flatten adds the values of object to cells under dotted keys, recording new keys in order.
+func flatten(prefix string, object yaml.MapSlice, cells map[string]string, keys *[]string) {
+	for _, item := range object {
+		key := fmt.Sprint(item.Key)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := item.Value.(yaml.MapSlice); ok && len(nested) > 0 {
+			flatten(key, nested, cells, keys)
+			continue
+		}
+		if _, ok := cells[key]; !ok {
+			*keys = append(*keys, key)
+		}
+		cells[key] = valueString(item.Value)
+	}
+}
+
+// valueString formats a value for a cell: lists of plain values are joined with commas and other
+// lists and objects are encoded as JSON.
+func valueString(value interface{}) string {
+	switch v := value.(type) {
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			switch item.(type) {
+			case []interface{}, yaml.MapSlice:
+				return compactJSON(v)
+			}
+			parts[i] = scalarString(item)
+		}
+		return strings.Join(parts, ", ")
+	case yaml.MapSlice:
+		return compactJSON(v)
+	}
+	return scalarString(value)
+}
+
+func scalarString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// compactJSON encodes a decoded value as JSON, keeping the key order of objects.
+func compactJSON(value interface{}) string {
+	var b strings.Builder
+	writeJSON(&b, value)
+	return b.String()
+}
+
+func writeJSON(b *strings.Builder, value interface{}) {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		b.WriteByte('{')
+		for i, item := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := marshalString(fmt.Sprint(item.Key))
+			b.Write(key)
+			b.WriteByte(':')
+			writeJSON(b, item.Value)
+		}
+		b.WriteByte('}')
+	case []interface{}:
+		b.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSON(b, item)
+		}
+		b.WriteByte(']')
+	case string:
+		s, _ := marshalString(v)
+		b.Write(s)
+	case nil:
+		b.WriteString("null")
+	default:
+		b.WriteString(scalarString(v))
+	}
+}
+
+// decimalNumber matches numbers written in plain decimal notation. A leading zero, as in a zip code
+// or an ID like "01234", exponents and spellings such as "NaN" or "0x1p3" do not match, so such
+// columns stay text and keep their values as written.
+var decimalNumber = regexp.MustCompile(`^[+-]?(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+
+// inferColumnTypes types the columns whose non-empty cells are all integers, or all numbers, in
+// plain decimal notation.
+func inferColumnTypes(table *Table) map[string]types.ColumnType {
+	hints := make(map[string]types.ColumnType, len(table.Headers))
+	for col, header := range table.Headers {
+		typ, seen := types.ColumnInt, false
+		for _, row := range table.Rows {
+			value := strings.TrimSpace(cell(row, col))
+			if value == "" {
+				continue
+			}
+			seen = true
+			if !decimalNumber.MatchString(value) {
+				typ = types.ColumnString
+				break
+			}
+			if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+				typ = types.ColumnFloat
+			}
+		}
+		if !seen {
+			typ = types.ColumnString
+		}
+		hints[header] = typ
+	}
+	return hints
+}
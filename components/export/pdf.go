@@ -0,0 +1,304 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/faelmori/xtui/types"
+)
+
+// Page layout of PDF reports, in points. Pages are A4 landscape and all text uses the standard
+// Courier fonts, whose fixed glyph width lets columns be measured without embedding font metrics.
+const (
+	pdfPageWidth    = 842.0
+	pdfPageHeight   = 595.0
+	pdfMargin       = 36.0
+	pdfTitleSize    = 14.0
+	pdfFontSize     = 8.0
+	pdfLeading      = 10.0
+	pdfCellPadding  = 3.0
+	pdfFooterHeight = 20.0
+	pdfMaxCellLines = 3
+	// pdfCharWidth is the advance of a Courier glyph as a fraction of the font size.
+	pdfCharWidth = 0.6
+	// pdfEllipsis is the horizontal ellipsis in WinAnsiEncoding.
+	pdfEllipsis = 0x85
+)
+
+// pdfTimeFormat is the layout of the generation timestamp printed on every page.
+const pdfTimeFormat = "2006-01-02 15:04:05"
+
+// pdfNow returns the generation time of a report.
+var pdfNow = time.Now
+
+// pdfWinAnsi maps the characters that WinAnsiEncoding places in the 0x80-0x9F range.
+var pdfWinAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// pdfRow is a laid out table row: the wrapped lines of every cell and the row height.
+type pdfRow struct {
+	cells  [][][]byte
+	height float64
+}
+
+// WritePDF writes the table as a paginated PDF report. Every page shows the title, the generation
+// time, the header row and its page number. Columns are shrunk to fit the page width and long cells
+// are wrapped over up to three lines, then truncated with an ellipsis.
+func WritePDF(w io.Writer, data types.TableDataHandler) error {
+	headers := data.GetHeaders()
+	rows := data.GetRows()
+	widths := pdfColumnWidths(headers, rows)
+
+	header := pdfRow{height: pdfLeading + 2*pdfCellPadding}
+	for i, h := range headers {
+		header.cells = append(header.cells, wrapPDFCell(h, widths[i], 1))
+	}
+
+	top := pdfPageHeight - pdfMargin - pdfTitleSize - pdfLeading
+	bottom := pdfMargin + pdfFooterHeight
+	pages := [][]pdfRow{nil}
+	y := top - header.height
+	for _, row := range rows {
+		laid := pdfRow{}
+		lines := 1
+		for i := range headers {
+			cellLines := wrapPDFCell(cell(row, i), widths[i], pdfMaxCellLines)
+			laid.cells = append(laid.cells, cellLines)
+			lines = max(lines, len(cellLines))
+		}
+		laid.height = float64(lines)*pdfLeading + 2*pdfCellPadding
+		if y-laid.height < bottom && len(pages[len(pages)-1]) > 0 {
+			pages = append(pages, nil)
+			y = top - header.height
+		}
+		pages[len(pages)-1] = append(pages[len(pages)-1], laid)
+		y -= laid.height
+	}
+
+	doc := &pdfDocument{}
+	doc.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	doc.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	doc.object(3, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	doc.object(4, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	generated := "Generated " + pdfNow().Format(pdfTimeFormat)
+	for i, page := range pages {
+		var c bytes.Buffer
+		writePDFPageHeader(&c, title(data), generated)
+		y := top
+		fmt.Fprintf(&c, "0.85 g %.2f %.2f %.2f %.2f re f 0 g\n", pdfMargin, y-header.height, pdfPageWidth-2*pdfMargin, header.height)
+		writePDFRow(&c, header, widths, y, "/F2")
+		y -= header.height
+		for _, row := range page {
+			writePDFRow(&c, row, widths, y, "/F1")
+			y -= row.height
+			fmt.Fprintf(&c, "0.8 G 0.5 w %.2f %.2f m %.2f %.2f l S 0 G\n", pdfMargin, y, pdfPageWidth-pdfMargin, y)
+		}
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(pages))
+		writePDFText(&c, "/F1", pdfFontSize, (pdfPageWidth-pdfTextWidth(len(footer), pdfFontSize))/2, pdfMargin, pdfEncode(footer))
+
+		stream, err := deflate(c.Bytes())
+		if err != nil {
+			return err
+		}
+		doc.object(5+2*i, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		doc.stream(6+2*i, stream)
+	}
+	return doc.writeTo(w)
+}
+
+// writePDFPageHeader writes the title on the left and the generation time on the right.
+func writePDFPageHeader(c *bytes.Buffer, title, generated string) {
+	y := pdfPageHeight - pdfMargin - pdfTitleSize
+	right := pdfPageWidth - pdfMargin - pdfTextWidth(len(generated), pdfFontSize)
+	if title != "" {
+		room := int((right - pdfMargin - pdfLeading) / pdfTextWidth(1, pdfTitleSize))
+		writePDFText(c, "/F2", pdfTitleSize, pdfMargin, y, wrapPDFCell(title, max(room, 1), 1)[0])
+	}
+	writePDFText(c, "/F1", pdfFontSize, right, y, pdfEncode(generated))
+}
+
+// writePDFRow writes the lines of every cell of the row whose top edge is at y.
+func writePDFRow(c *bytes.Buffer, row pdfRow, widths []int, y float64, font string) {
+	x := pdfMargin
+	for i, lines := range row.cells {
+		for j, line := range lines {
+			baseline := y - pdfCellPadding - float64(j+1)*pdfLeading + (pdfLeading - pdfFontSize)
+			writePDFText(c, font, pdfFontSize, x+pdfCellPadding, baseline, line)
+		}
+		x += pdfTextWidth(widths[i], pdfFontSize) + 2*pdfCellPadding
+	}
+}
+
+func writePDFText(c *bytes.Buffer, font string, size, x, y float64, text []byte) {
+	if len(text) == 0 {
+		return
+	}
+	fmt.Fprintf(c, "BT %s %g Tf %.2f %.2f Td (", font, size, x, y)
+	for _, b := range text {
+		if b == '\\' || b == '(' || b == ')' {
+			c.WriteByte('\\')
+		}
+		c.WriteByte(b)
+	}
+	c.WriteString(") Tj ET\n")
+}
+
+func pdfTextWidth(chars int, size float64) float64 {
+	return float64(chars) * pdfCharWidth * size
+}
+
+// pdfColumnWidths returns the width of every column, in characters. Columns keep their natural
+// width when everything fits the page; otherwise the narrow columns keep theirs and the page width
+// left is shared evenly by the wider ones.
+func pdfColumnWidths(headers []string, rows [][]string) []int {
+	natural := make([]int, len(headers))
+	for i, header := range headers {
+		natural[i] = max(len([]rune(header)), 1)
+	}
+	for _, row := range rows {
+		for i := range headers {
+			natural[i] = max(natural[i], len([]rune(cell(row, i))))
+		}
+	}
+
+	usable := pdfPageWidth - 2*pdfMargin - float64(len(headers))*2*pdfCellPadding
+	available := int(usable / pdfTextWidth(1, pdfFontSize))
+	widths := make([]int, len(headers))
+	remaining := make([]int, 0, len(headers))
+	for i := range headers {
+		remaining = append(remaining, i)
+	}
+	for len(remaining) > 0 {
+		share := available / len(remaining)
+		var wide []int
+		for _, i := range remaining {
+			if natural[i] <= share {
+				widths[i] = natural[i]
+				available -= natural[i]
+			} else {
+				wide = append(wide, i)
+			}
+		}
+		if len(wide) == len(remaining) {
+			for _, i := range wide {
+				widths[i] = max(share, 1)
+			}
+			break
+		}
+		remaining = wide
+	}
+	return widths
+}
+
+// wrapPDFCell encodes the cell and wraps it to width characters, breaking at spaces when possible.
+// Text beyond maxLines is cut and marked with an ellipsis.
+func wrapPDFCell(s string, width, maxLines int) [][]byte {
+	text := pdfEncode(strings.Join(strings.Fields(s), " "))
+	var lines [][]byte
+	for len(text) > width {
+		cut := bytes.LastIndexByte(text[:width+1], ' ')
+		if cut <= 0 {
+			cut = width
+		}
+		lines = append(lines, bytes.TrimRight(text[:cut], " "))
+		text = bytes.TrimLeft(text[cut:], " ")
+		if len(lines) == maxLines {
+			last := lines[maxLines-1]
+			if len(last) >= width {
+				last = last[:width-1]
+			}
+			lines[maxLines-1] = append(last[:len(last):len(last)], pdfEllipsis)
+			return lines
+		}
+	}
+	return append(lines, text)
+}
+
+// pdfEncode converts the text to WinAnsiEncoding, replacing characters it cannot represent.
+func pdfEncode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			out = append(out, ' ')
+		case r >= 0x20 && r < 0x7F, r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		default:
+			if b, ok := pdfWinAnsi[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var b bytes.Buffer
+	zw := zlib.NewWriter(&b)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// pdfDocument collects numbered objects and writes them with their cross-reference table.
+type pdfDocument struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+	last    int
+}
+
+func (d *pdfDocument) begin(num int) {
+	if d.buf.Len() == 0 {
+		d.buf.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	}
+	if d.offsets == nil {
+		d.offsets = make(map[int]int)
+	}
+	d.offsets[num] = d.buf.Len()
+	d.last = max(d.last, num)
+	fmt.Fprintf(&d.buf, "%d 0 obj\n", num)
+}
+
+func (d *pdfDocument) object(num int, body string) {
+	d.begin(num)
+	d.buf.WriteString(body)
+	d.buf.WriteString("\nendobj\n")
+}
+
+func (d *pdfDocument) stream(num int, data []byte) {
+	d.begin(num)
+	fmt.Fprintf(&d.buf, "<< /Length %d /Filter /FlateDecode >>\nstream\n", len(data))
+	d.buf.Write(data)
+	d.buf.WriteString("\nendstream\nendobj\n")
+}
+
+func (d *pdfDocument) writeTo(w io.Writer) error {
+	xref := d.buf.Len()
+	fmt.Fprintf(&d.buf, "xref\n0 %d\n0000000000 65535 f \n", d.last+1)
+	for num := 1; num <= d.last; num++ {
+		fmt.Fprintf(&d.buf, "%010d 00000 n \n", d.offsets[num])
+	}
+	fmt.Fprintf(&d.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", d.last+1, xref)
+	_, err := w.Write(d.buf.Bytes())
+	return err
+}
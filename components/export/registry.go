@@ -0,0 +1,185 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/faelmori/xtui/types"
+)
+
+// Exporter writes the headers and rows of a table handler to w in a specific format.
+type Exporter interface {
+	Export(w io.Writer, data types.TableDataHandler) error
+}
+
+// ExporterFunc adapts a plain function to the Exporter interface.
+type ExporterFunc func(w io.Writer, data types.TableDataHandler) error
+
+func (f ExporterFunc) Export(w io.Writer, data types.TableDataHandler) error { return f(w, data) }
+
+// Format is a registered export format. Extension is used to name files and to guess the format
+// of a file name, without the leading dot.
+type Format struct {
+	Name      string
+	Extension string
+	Exporter  Exporter
+}
+
+// Registry maps format names to exporters. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	formats map[string]Format
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{formats: make(map[string]Format)}
+}
+
+// Register adds or replaces the exporter for a format name. Names are case-insensitive.
+func (r *Registry) Register(name, extension string, exporter Exporter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name = strings.ToLower(name)
+	r.formats[name] = Format{Name: name, Extension: strings.TrimPrefix(extension, "."), Exporter: exporter}
+}
+
+// Get returns the format registered under name.
+func (r *Registry) Get(name string) (Format, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.formats[strings.ToLower(name)]
+	return f, ok
+}
+
+// Formats returns the registered format names in alphabetical order.
+func (r *Registry) Formats() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.formatNames()
+}
+
+// FormatForFile returns the name of the format whose extension matches the file name.
+func (r *Registry) FormatForFile(filename string) (string, bool) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	if ext == "" {
+		return "", false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, name := range r.formatNames() {
+		if r.formats[name].Extension == ext {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// formatNames returns the sorted names without locking, for callers that hold the lock.
+func (r *Registry) formatNames() []string {
+	names := make([]string, 0, len(r.formats))
+	for name := range r.formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Export writes data to w using the named format.
+func (r *Registry) Export(format string, w io.Writer, data types.TableDataHandler) error {
+	f, ok := r.Get(format)
+	if !ok {
+		return fmt.Errorf("export format %q is not registered", format)
+	}
+	return f.Exporter.Export(w, data)
+}
+
+// ExportToFile writes data to filename using the named format. An empty format is guessed from the
+// file extension. The data is written to a temporary file in the same directory, renamed to
+// filename once complete, so a failed export leaves no partial file behind and keeps an existing
+// one intact.
+func (r *Registry) ExportToFile(format, filename string, data types.TableDataHandler) error {
+	if format == "" {
+		guessed, ok := r.FormatForFile(filename)
+		if !ok {
+			return fmt.Errorf("no export format for file %q", filename)
+		}
+		format = guessed
+	}
+	if _, ok := r.Get(format); !ok {
+		return fmt.Errorf("export format %q is not registered", format)
+	}
+	return WriteFileAtomic(filename, func(w io.Writer) error {
+		return r.Export(format, w, data)
+	})
+}
+
+// WriteFileAtomic writes a file through write into a temporary file next to it, renamed over the
+// file once complete, so a failed or interrupted write leaves the previous file intact. An existing
+// file keeps its permissions.
+func WriteFileAtomic(filename string, write func(w io.Writer) error) error {
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+	file, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), mode)
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filename)
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+	}
+	return err
+}
+
+// Default is the registry used by the package level functions, preloaded with the built-in formats.
+var Default = NewRegistry()
+
+func init() {
+	Default.Register("csv", "csv", ExporterFunc(WriteCSV))
+	Default.Register("tsv", "tsv", ExporterFunc(WriteTSV))
+	Default.Register("json", "json", ExporterFunc(WriteJSON))
+	Default.Register("ndjson", "ndjson", ExporterFunc(WriteNDJSON))
+	Default.Register("yaml", "yaml", ExporterFunc(WriteYAML))
+	Default.Register("xml", "xml", ExporterFunc(WriteXML))
+	Default.Register("markdown", "md", ExporterFunc(WriteMarkdown))
+	Default.Register("html", "html", ExporterFunc(WriteHTML))
+	Default.Register("xlsx", "xlsx", ExporterFunc(WriteXLSX))
+	Default.Register("pdf", "pdf", ExporterFunc(WritePDF))
+}
+
+// Register adds or replaces a format in the default registry.
+func Register(name, extension string, exporter Exporter) {
+	Default.Register(name, extension, exporter)
+}
+
+// Formats returns the format names of the default registry.
+func Formats() []string { return Default.Formats() }
+
+// Get returns a format of the default registry.
+func Get(name string) (Format, bool) { return Default.Get(name) }
+
+// Export writes data to w with a format of the default registry.
+func Export(format string, w io.Writer, data types.TableDataHandler) error {
+	return Default.Export(format, w, data)
+}
+
+// ExportToFile writes data to filename with a format of the default registry.
+func ExportToFile(format, filename string, data types.TableDataHandler) error {
+	return Default.ExportToFile(format, filename, data)
+}
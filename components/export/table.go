@@ -0,0 +1,88 @@
+package export
+
+import (
+	"github.com/faelmori/xtui/types"
+)
+
+// Table is a plain in-memory TableDataHandler. Types optionally holds column type hints and Title
+// the caption used by report formats.
+type Table struct {
+	Title   string
+	Headers []string
+	Rows    [][]string
+	Types   map[string]types.ColumnType
+}
+
+func (t *Table) GetHeaders() []string                        { return t.Headers }
+func (t *Table) GetRows() [][]string                         { return t.Rows }
+func (t *Table) GetColumnTypes() map[string]types.ColumnType { return t.Types }
+func (t *Table) GetTitle() string                            { return t.Title }
+
+// NewTable wraps headers and rows into a TableDataHandler.
+func NewTable(headers []string, rows [][]string) *Table {
+	return &Table{Headers: headers, Rows: rows}
+}
+
+// Project returns a handler with only the given columns of data, in the given order. Unknown
+// column names are skipped.
+func Project(data types.TableDataHandler, columns []string) *Table {
+	headers := data.GetHeaders()
+	index := make(map[string]int, len(headers))
+	for i, header := range headers {
+		index[header] = i
+	}
+	var cols []int
+	var projected []string
+	for _, column := range columns {
+		if i, ok := index[column]; ok {
+			cols = append(cols, i)
+			projected = append(projected, column)
+		}
+	}
+	rows := data.GetRows()
+	out := make([][]string, len(rows))
+	for r, row := range rows {
+		out[r] = make([]string, len(cols))
+		for c, col := range cols {
+			if col < len(row) {
+				out[r][c] = row[col]
+			}
+		}
+	}
+	table := NewTable(projected, out)
+	table.Types = columnTypes(data)
+	table.Title = title(data)
+	return table
+}
+
+// title returns the title of data, or an empty string when it has none.
+func title(data types.TableDataHandler) string {
+	if titler, ok := data.(types.TableTitler); ok {
+		return titler.GetTitle()
+	}
+	return ""
+}
+
+// columnTypes returns the type hint of every column of data, ColumnString when it has none.
+func columnTypes(data types.TableDataHandler) map[string]types.ColumnType {
+	hints := make(map[string]types.ColumnType)
+	if typer, ok := data.(types.TableColumnTyper); ok {
+		for header, typ := range typer.GetColumnTypes() {
+			hints[header] = typ
+		}
+	}
+	for _, header := range data.GetHeaders() {
+		if _, ok := hints[header]; !ok {
+			hints[header] = types.ColumnString
+		}
+	}
+	return hints
+}
+
+// cell returns the value of column i of row, or an empty string for short rows.
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
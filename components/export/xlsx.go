@@ -0,0 +1,265 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/faelmori/xtui/types"
+)
+
+const (
+	xlsxSheetName     = "Sheet1"
+	xlsxMaxColWidth   = 80
+	xlsxMinColWidth   = 6
+	xlsxHeaderStyle   = 1
+	xlsxDateStyle     = 2
+	xlsxTimeStyle     = 3
+	xlsxWidthPadding  = 2
+	xlsxFilterPadding = 3
+)
+
+// xlsxUnixEpoch is the serial date number of 1970-01-01 in the Excel 1900 date system, which counts
+// days from 1899-12-30.
+const xlsxUnixEpoch = 25569
+
+// xlsxFirstDate is the first date written as a serial number. Earlier dates have no serial number,
+// or one shifted by the leap day Excel counts in 1900, so they are written as text.
+var xlsxFirstDate = time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+// xlsxStyles defines the cell formats referenced by the sheet: 0 is the default, 1 the bold
+// header, 2 the date/time format and 3 the time of day format.
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/><numFmt numFmtId="165" formatCode="hh:mm:ss"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="4">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+</cellXfs>
+<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>
+</styleSheet>`
+
+// WriteXLSX writes an Office Open XML workbook with a single sheet. The header row is bold and
+// frozen, an auto-filter covers the data and the columns are sized to their content. Columns
+// with a numeric, byte size or time type hint are written as numbers and dates.
+func WriteXLSX(w io.Writer, data types.TableDataHandler) error {
+	headers := data.GetHeaders()
+	rows := data.GetRows()
+	hints := columnTypes(data)
+
+	zw := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content func(io.Writer) error
+	}{
+		{"[Content_Types].xml", writeString(xlsxContentTypes)},
+		{"_rels/.rels", writeString(xlsxRootRels)},
+		{"xl/workbook.xml", func(pw io.Writer) error { return writeXLSXWorkbook(pw, len(headers), len(rows)) }},
+		{"xl/_rels/workbook.xml.rels", writeString(xlsxWorkbookRels)},
+		{"xl/styles.xml", writeString(xlsxStyles)},
+		{"xl/worksheets/sheet1.xml", func(pw io.Writer) error { return writeXLSXSheet(pw, headers, rows, hints) }},
+	}
+	for _, part := range parts {
+		pw, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if err := part.content(pw); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeString(s string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, s)
+		return err
+	}
+}
+
+func writeXLSXWorkbook(w io.Writer, cols, rows int) error {
+	_, err := fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+<definedNames><definedName name="_xlnm._FilterDatabase" localSheetId="0" hidden="1">'%s'!%s</definedName></definedNames>
+</workbook>`, xlsxSheetName, xlsxSheetName, xlsxAbsoluteRange(cols, rows+1))
+	return err
+}
+
+func writeXLSXSheet(w io.Writer, headers []string, rows [][]string, hints map[string]types.ColumnType) error {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/><selection pane="bottomLeft" activeCell="A2" sqref="A2"/></sheetView></sheetViews>`)
+	b.WriteString(`<sheetFormatPr defaultRowHeight="15"/>`)
+
+	if len(headers) > 0 {
+		b.WriteString("<cols>")
+		for i, width := range xlsxColumnWidths(headers, rows) {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString("</cols>")
+	}
+
+	b.WriteString("<sheetData>")
+	b.WriteString(`<row r="1">`)
+	for i, header := range headers {
+		writeXLSXStringCell(&b, xlsxCellRef(i, 1), header, xlsxHeaderStyle)
+	}
+	b.WriteString("</row>")
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+
+	for r, row := range rows {
+		b.Reset()
+		fmt.Fprintf(&b, `<row r="%d">`, r+2)
+		for i, header := range headers {
+			writeXLSXCell(&b, xlsxCellRef(i, r+2), cell(row, i), hints[header])
+		}
+		b.WriteString("</row>")
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+
+	b.Reset()
+	b.WriteString("</sheetData>")
+	if len(headers) > 0 {
+		fmt.Fprintf(&b, `<autoFilter ref="%s:%s"/>`, xlsxCellRef(0, 1), xlsxCellRef(len(headers)-1, len(rows)+1))
+	}
+	b.WriteString("</worksheet>")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeXLSXCell writes a typed cell when the value parses as the column type, with the same parsers
+// the table sorts with, so "1,234" is a number and "12 MB" a size; other values are text.
+func writeXLSXCell(b *strings.Builder, ref, value string, typ types.ColumnType) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	switch typ {
+	case types.ColumnInt, types.ColumnFloat:
+		if v, err := types.ParseNumber(value); err == nil && !math.IsInf(v, 0) {
+			fmt.Fprintf(b, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+			return
+		}
+	case types.ColumnBytes:
+		if size, err := types.ParseByteSize(value); err == nil {
+			fmt.Fprintf(b, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(size, 'f', -1, 64))
+			return
+		}
+	case types.ColumnTime:
+		if t, err := types.ParseTime(value); err == nil {
+			if serial, style, ok := xlsxSerialDate(t); ok {
+				fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(serial, 'f', -1, 64))
+				return
+			}
+		}
+	}
+	writeXLSXStringCell(b, ref, value, 0)
+}
+
+// xlsxSerialDate returns the serial date number of t and the style showing it. A time without a
+// date, parsed into year 0, is a fraction of a day shown as a time of day. ok is false for dates
+// before xlsxFirstDate.
+func xlsxSerialDate(t time.Time) (serial float64, style int, ok bool) {
+	// spreadsheets have no time zones, so keep the wall clock time as written
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	if t.Year() == 0 {
+		seconds := float64(t.Hour()*3600+t.Minute()*60+t.Second()) + float64(t.Nanosecond())/1e9
+		return seconds / 86400, xlsxTimeStyle, true
+	}
+	if wall.Before(xlsxFirstDate) {
+		return 0, 0, false
+	}
+	return xlsxDays(wall), xlsxDateStyle, true
+}
+
+// xlsxDays returns the days from the Excel epoch to t, from its Unix time so far dates do not
+// overflow a time.Duration.
+func xlsxDays(t time.Time) float64 {
+	return (float64(t.Unix())+float64(t.Nanosecond())/1e9)/86400 + xlsxUnixEpoch
+}
+
+func writeXLSXStringCell(b *strings.Builder, ref, value string, style int) {
+	fmt.Fprintf(b, `<c r="%s" t="inlineStr"`, ref)
+	if style > 0 {
+		fmt.Fprintf(b, ` s="%d"`, style)
+	}
+	b.WriteString(`><is><t xml:space="preserve">`)
+	_ = xml.EscapeText(b, []byte(value))
+	b.WriteString("</t></is></c>")
+}
+
+// xlsxColumnWidths sizes each column to its longest value, in characters, leaving room for the
+// auto-filter button on the header.
+func xlsxColumnWidths(headers []string, rows [][]string) []int {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = utf8.RuneCountInString(header) + xlsxFilterPadding
+	}
+	for _, row := range rows {
+		for i := range headers {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell(row, i)))
+		}
+	}
+	for i := range widths {
+		widths[i] = min(max(widths[i]+xlsxWidthPadding, xlsxMinColWidth), xlsxMaxColWidth)
+	}
+	return widths
+}
+
+// xlsxColumnName converts a zero based column index into its letters: 0 is A, 26 is AA.
+func xlsxColumnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
+
+func xlsxCellRef(col, row int) string {
+	return xlsxColumnName(col) + strconv.Itoa(row)
+}
+
+func xlsxAbsoluteRange(cols, rows int) string {
+	if cols == 0 {
+		cols = 1
+	}
+	return fmt.Sprintf("$A$1:$%s$%d", xlsxColumnName(cols-1), rows)
+}
@@ -1,8 +1,11 @@
 package components
 
 import (
+	"errors"
 	"fmt"
 	"github.com/faelmori/logz"
+	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/cursor"
@@ -20,25 +23,32 @@ var (
 	helpStyle           = blurredStyle
 	cursorModeHelpStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
 	errorStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("160"))
+	sectionStyle        = lipgloss.NewStyle().Bold(true).Underline(true)
 
 	focusedButton = focusedStyle.Render("[ Proceed ]")
 	blurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("Proceed"))
 )
 
-var inputResult map[string]string
+// ErrFormCanceled is returned when a form is closed without being submitted.
+var ErrFormCanceled = errors.New("form canceled")
 
 type FormModel struct {
 	Title        string
 	FocusIndex   int
 	Inputs       []textinput.Model
 	CursorMode   cursor.Mode
-	Fields       []FormInputObject[any]
+	Fields       []FormField
 	ErrorMessage string
+
+	names       []string
+	widgets     []formWidget
+	fieldErrors *FieldErrors
+	result      *FormResult
 }
 
 func initialFormModel(config Config) FormModel {
 	cfg := &config
-	var inputs []FormInputObject[any]
+	var inputs []FormField
 
 	for _, field := range cfg.Fields.Inputs() {
 		inputs = append(inputs, field)
@@ -49,40 +59,116 @@ func initialFormModel(config Config) FormModel {
 		inputs = adaptInputsToProperties(inputs, availableProperties)
 	}
 
+	return newFormModel(cfg.Title, inputs)
+}
+
+// newFormModel creates a form with an input for each field. Bool fields and fields with options are
+// edited with a widget (see newFormWidget), whose text input only holds its value. When fields have
+// labels, every input shows its label, or its name, as the prompt. Fields are keyed by
+// FormFieldNames, so an empty or repeated name falls back to the position of the field.
+func newFormModel(title string, inputs []FormField) FormModel {
+	inputs = readFieldOptions(inputs)
 	m := FormModel{
-		Title:        cfg.Title,
+		Title:        title,
 		FocusIndex:   0,
 		CursorMode:   cursor.CursorBlink,
-		Fields:       config.Fields.Inputs(),
+		Fields:       inputs,
 		Inputs:       make([]textinput.Model, len(inputs)),
 		ErrorMessage: "",
+		names:        FormFieldNames(inputs),
+		widgets:      make([]formWidget, len(inputs)),
+		fieldErrors:  NewFieldErrors(),
+	}
+
+	labels, labelWidth := make([]string, len(inputs)), 0
+	for i, field := range inputs {
+		labels[i] = fieldLabel(field)
+		labelWidth = max(labelWidth, lipgloss.Width(labels[i]))
+	}
+	if labelWidth > 0 {
+		for i := range inputs {
+			if labels[i] == "" {
+				labels[i] = m.names[i]
+			}
+			labelWidth = max(labelWidth, lipgloss.Width(labels[i]))
+		}
 	}
 
 	var t textinput.Model
 	for i, field := range inputs {
 		t = textinput.New()
 		t.Cursor.Style = cursorStyle
-		t.CharLimit = 32
-		t.Placeholder = field.(FormInput[any]).Placeholder()
-		t.SetValue(field.(FormInput[any]).String())
+		t.CharLimit = 0
+		if field.Type() != FieldInt && field.MaxValue() > 0 {
+			t.CharLimit = field.MaxValue()
+		}
+		t.Placeholder = field.Placeholder()
+		t.SetValue(field.Value())
+		if labelWidth > 0 {
+			t.Prompt = labels[i] + ": " + strings.Repeat(" ", labelWidth-lipgloss.Width(labels[i]))
+		}
 
-		if field.(FormInput[any]).GetType().String() == "password" {
+		if field.Type() == FieldPass {
 			t.EchoMode = textinput.EchoPassword
 			t.EchoCharacter = '•'
 		}
 
-		if i == 0 {
-			t.Focus()
-			t.PromptStyle = focusedStyle
-			t.TextStyle = focusedStyle
+		w := newFormWidget(field)
+		if w != nil {
+			m.widgets[i] = w
+			t.CharLimit = 0
+			t.SetValue(w.Value())
 		}
 
 		m.Inputs[i] = t
+		// a widget drops a preset value that is not one of its options, so the error is shown
+		// until the field is changed
+		if w != nil && w.Value() != field.Value() {
+			m.fieldErrors.Set(m.names[i], field.Validate(field.Value()))
+		}
 	}
+	m.focus(0)
 
 	return m
 }
 
+// readFieldOptions calls the option providers of the fields once, so the widgets and the validation
+// of the fields share the same options for the life of the form. Fields with a provider are
+// replaced by copies holding its options; the fields of the caller are left untouched.
+func readFieldOptions(inputs []FormField) []FormField {
+	inputs = slices.Clone(inputs)
+	for i, field := range inputs {
+		if f, ok := field.(*InputField); ok && f.OptsFn != nil {
+			read := *f
+			read.Opts, read.OptsFn = f.OptsFn(), nil
+			inputs[i] = &read
+		}
+	}
+	return inputs
+}
+
+// fieldLabel returns the label of the field, or "" when it has none.
+func fieldLabel(field FormField) string {
+	if details, ok := field.(FormFieldDetails); ok && details.Label() != "" {
+		return details.Label()
+	}
+	return ""
+}
+
+func fieldHelp(field FormField) string {
+	if details, ok := field.(FormFieldDetails); ok {
+		return details.Help()
+	}
+	return ""
+}
+
+func fieldGroup(field FormField) string {
+	if details, ok := field.(FormFieldDetails); ok {
+		return details.Group()
+	}
+	return ""
+}
+
 func (m *FormModel) Init() tea.Cmd {
 	return textinput.Blink
 }
@@ -90,6 +176,14 @@ func (m *FormModel) Init() tea.Cmd {
 func (m *FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if w := m.widget(m.FocusIndex); w != nil && w.Update(msg) {
+			m.Inputs[m.FocusIndex].SetValue(w.Value())
+			if m.fieldErrors.Get(m.names[m.FocusIndex]) != nil {
+				m.validateField(m.FocusIndex)
+			}
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "esc":
 			return m, tea.Quit
@@ -111,32 +205,25 @@ func (m *FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.submit()
 			}
 
-			if s == "up" || s == "shift+tab" {
-				m.FocusIndex--
-			} else {
-				m.FocusIndex++
+			// the field losing the focus is validated, so its errors show right away
+			if m.FocusIndex < len(m.Inputs) {
+				m.validateField(m.FocusIndex)
 			}
 
-			if m.FocusIndex > len(m.Inputs) {
-				m.FocusIndex = 0
-			} else if m.FocusIndex < 0 {
-				m.FocusIndex = len(m.Inputs)
+			next := m.FocusIndex
+			if s == "up" || s == "shift+tab" {
+				next--
+			} else {
+				next++
 			}
 
-			cmds := make([]tea.Cmd, len(m.Inputs))
-			for i := 0; i <= len(m.Inputs)-1; i++ {
-				if i == m.FocusIndex {
-					cmds[i] = m.Inputs[i].Focus()
-					m.Inputs[i].PromptStyle = focusedStyle
-					m.Inputs[i].TextStyle = focusedStyle
-					continue
-				}
-				m.Inputs[i].Blur()
-				m.Inputs[i].PromptStyle = noStyle
-				m.Inputs[i].TextStyle = noStyle
+			if next > len(m.Inputs) {
+				next = 0
+			} else if next < 0 {
+				next = len(m.Inputs)
 			}
 
-			return m, tea.Batch(cmds...)
+			return m, m.focus(next)
 		}
 	}
 
@@ -150,8 +237,24 @@ func (m *FormModel) View() string {
 
 	b.WriteString(fmt.Sprintf("\n%s\n\n", m.Title))
 
+	group := ""
 	for i := range m.Inputs {
-		b.WriteString(m.Inputs[i].View())
+		if g := fieldGroup(m.Fields[i]); g != group {
+			if i > 0 {
+				b.WriteRune('\n')
+			}
+			if g != "" {
+				b.WriteString(sectionStyle.Render(g) + "\n")
+			}
+			group = g
+		}
+		b.WriteString(m.inputView(i))
+		if err := m.fieldErrors.Get(m.names[i]); err != nil {
+			b.WriteString("\n  " + errorStyle.Render("✗ "+err.Error()))
+		}
+		if help := fieldHelp(m.Fields[i]); help != "" && i == m.FocusIndex {
+			b.WriteString("\n  " + helpStyle.Render(help))
+		}
 		if i < len(m.Inputs)-1 {
 			b.WriteRune('\n')
 		}
@@ -175,65 +278,156 @@ func (m *FormModel) View() string {
 	return b.String()
 }
 
-func (m *FormModel) submit() tea.Cmd {
-	for i, input := range m.Inputs {
-		value := input.Value()
-		field := m.Fields[i].(FormInput[any])
+// inputView renders the input at index i, or the widget editing its field after its prompt.
+func (m *FormModel) inputView(i int) string {
+	w := m.widget(i)
+	if w == nil {
+		return m.Inputs[i].View()
+	}
+	prompt := m.Inputs[i].Prompt
+	indent := strings.Repeat(" ", lipgloss.Width(prompt))
+	return m.Inputs[i].PromptStyle.Render(prompt) + w.View(i == m.FocusIndex, indent)
+}
 
-		if field.IsRequired() && value == "" {
-			m.ErrorMessage = field.Error()
-			return nil
-		}
-		if field.MinValue() > 0 && len(value) < field.MinValue() {
-			m.ErrorMessage = field.Error()
-			return nil
+// widget returns the widget editing the field at index i, or nil when the field is edited in its
+// text input.
+func (m *FormModel) widget(i int) formWidget {
+	if i < 0 || i >= len(m.widgets) {
+		return nil
+	}
+	return m.widgets[i]
+}
+
+// fieldValue returns the value of the field at index i for the form result: a bool for toggles, a
+// []string for multi-selects and text for the other fields.
+func (m *FormModel) fieldValue(i int) interface{} {
+	if w := m.widget(i); w != nil {
+		return w.Result()
+	}
+	return m.Inputs[i].Value()
+}
+
+// focus moves the focus to the input at index i, or to the submit button when i is past the last
+// input.
+func (m *FormModel) focus(i int) tea.Cmd {
+	m.FocusIndex = i
+	cmds := make([]tea.Cmd, len(m.Inputs))
+	for i := range m.Inputs {
+		// widgets take the keys of their field, so their text input is never focused
+		if i == m.FocusIndex && m.widget(i) == nil {
+			cmds[i] = m.Inputs[i].Focus()
+		} else {
+			m.Inputs[i].Blur()
 		}
-		if field.MaxValue() > 0 && len(value) > field.MaxValue() {
-			m.ErrorMessage = field.Error()
-			return nil
+		m.styleInput(i)
+	}
+	return tea.Batch(cmds...)
+}
+
+// styleInput styles the input at index i by focus, with the prompt of invalid inputs in red.
+func (m *FormModel) styleInput(i int) {
+	style := noStyle
+	if i == m.FocusIndex {
+		style = focusedStyle
+	}
+	m.Inputs[i].TextStyle = style
+	if m.fieldErrors.Get(m.names[i]) != nil {
+		style = errorStyle
+	}
+	m.Inputs[i].PromptStyle = style
+}
+
+// validateField validates the input at index i and records or clears its error.
+func (m *FormModel) validateField(i int) {
+	m.fieldErrors.Set(m.names[i], m.Fields[i].Validate(m.Inputs[i].Value()))
+	m.styleInput(i)
+	if m.fieldErrors.Len() == 0 {
+		m.ErrorMessage = ""
+	}
+}
+
+// Validate validates every field and returns their errors as a *FieldErrors keyed by field name,
+// or nil when the form is valid. The errors are shown under their inputs.
+func (m *FormModel) Validate() error {
+	m.fieldErrors = ValidateFields(m.Fields, func(i int) string { return m.Inputs[i].Value() })
+	for i := range m.Inputs {
+		m.styleInput(i)
+	}
+	return m.fieldErrors.ErrorOrNil()
+}
+
+// Errors returns the errors of the invalid fields, as of their last validation.
+func (m *FormModel) Errors() *FieldErrors {
+	return m.fieldErrors
+}
+
+func (m *FormModel) submit() tea.Cmd {
+	if err := m.Validate(); err != nil {
+		m.ErrorMessage = "1 field is invalid"
+		if n := m.fieldErrors.Len(); n > 1 {
+			m.ErrorMessage = fmt.Sprintf("%d fields are invalid", n)
 		}
-		if field.Validation()(value, nil) != nil {
-			if err := field.Validation()(value, nil); err != nil {
-				m.ErrorMessage = err.Error()
-				return nil
+		for i, name := range m.names {
+			if m.fieldErrors.Get(name) != nil {
+				return m.focus(i)
 			}
 		}
+		return nil
+	}
 
-		inputResult[fmt.Sprintf("field%d", i)] = value
+	result := NewFormResult()
+	for i := range m.Inputs {
+		result.Set(m.names[i], m.fieldValue(i))
 	}
 
 	m.ErrorMessage = ""
-	DisplayNotification("Form submitted successfully", "info")
+	m.result = result
+	DisplayInfoNotification("Form submitted successfully")
 	return tea.Quit
 }
 
-func ShowForm(config Config) (map[string]string, error) {
-	inputResult = make(map[string]string)
-	var newConfig Config
-	var newFields = config.Fields.Inputs()
-	if newFields == nil {
-		iNewConfig := FormConfig{
-			Title:  config.Title,
-			Fields: nil,
-		}
-		newConfig = Config{
-			Title: iNewConfig.Title,
-			Fields: FormFields{
-				Title:  iNewConfig.Title,
-				Fields: config.GetFields().Inputs(),
-			},
-		}
-	}
-	initialModel := initialFormModel(newConfig)
-	_, resultModelErr := tea.NewProgram(&initialModel).Run()
+// Result returns the values of the submitted form, keyed by field name, or nil when the form was
+// not submitted.
+func (m *FormModel) Result() *FormResult {
+	return m.result
+}
+
+// runForm runs the form until it is submitted or canceled.
+func runForm(model FormModel, context string) (*FormResult, error) {
+	_, resultModelErr := tea.NewProgram(&model).Run()
 	if resultModelErr != nil {
 		logz.Error("Error running form model.", map[string]interface{}{
-			"context": "ShowForm",
+			"context": context,
 			"error":   resultModelErr,
 		})
 		return nil, resultModelErr
 	}
-	return inputResult, nil
+	if model.Result() == nil {
+		return nil, ErrFormCanceled
+	}
+	return model.Result(), nil
+}
+
+// ShowForm runs the form and returns its values, keyed by field name. ErrFormCanceled is returned
+// when the form is closed without being submitted.
+func ShowForm(config Config) (*FormResult, error) {
+	return runForm(initialFormModel(config), "ShowForm")
+}
+
+// FormFor shows a form editing the struct v points to and, once submitted, stores the values back
+// into it. The fields are built by StructFormFields from the `xtui` tags of the struct, and the
+// struct is left untouched when the form is canceled.
+func FormFor(v interface{}) error {
+	fields, err := StructFormFields(v)
+	if err != nil {
+		return err
+	}
+	title := reflect.TypeOf(v).Elem().Name()
+	result, err := runForm(newFormModel(title, fields), "FormFor")
+	if err != nil {
+		return err
+	}
+	return result.Decode(v)
 }
 
 func (m *FormModel) updateInputs(msg tea.Msg) tea.Cmd {
@@ -243,6 +437,11 @@ func (m *FormModel) updateInputs(msg tea.Msg) tea.Cmd {
 		m.Inputs[i], cmds[i] = m.Inputs[i].Update(msg)
 	}
 
+	// an invalid field is validated again as it is edited, so its error goes away once fixed
+	if i := m.FocusIndex; i < len(m.Inputs) && m.fieldErrors.Get(m.names[i]) != nil {
+		m.validateField(i)
+	}
+
 	return tea.Batch(cmds...)
 }
 
@@ -253,10 +452,11 @@ func getAvailableProperties() map[string]string {
 	}
 }
 
-func adaptInputsToProperties(inputs []FormInputObject[any], properties map[string]string) []FormInputObject[any] {
+func adaptInputsToProperties(inputs []FormField, properties map[string]string) []FormField {
 	adaptedInputs := inputs
 	for key, value := range properties {
-		adaptedInputs = append(adaptedInputs, NewFormInputObject(&InputField{
+		adaptedInputs = append(adaptedInputs, &InputField{
+			Nm:  key,
 			Ph:  key,
 			Tp:  "text",
 			Val: value,
@@ -265,53 +465,26 @@ func adaptInputsToProperties(inputs []FormInputObject[any], properties map[strin
 			Max: 100,
 			Err: "",
 			Vld: func(value string) error { return nil },
-		}))
+		})
 	}
 	return adaptedInputs
 }
 
-func NavigateAndExecuteForm(config Config) (map[string]string, error) {
-	inputResult = make(map[string]string)
-	initialModel := initialFormModel(config)
-	_, resultModelErr := tea.NewProgram(&initialModel).Run()
-	if resultModelErr != nil {
-		logz.Error("Error running form model.", map[string]interface{}{
-			"context": "NavigateAndExecuteForm",
-			"error":   resultModelErr,
-		})
-		return nil, resultModelErr
+func NavigateAndExecuteForm(config Config) (*FormResult, error) {
+	result, err := runForm(initialFormModel(config), "NavigateAndExecuteForm")
+	if err != nil {
+		return nil, err
 	}
-	DisplayNotification("Form submitted successfully", "info")
-	return inputResult, nil
+	DisplayInfoNotification("Form submitted successfully")
+	return result, nil
 }
 
-func ShowFormWithNotification(config Config) (map[string]string, error) {
-	inputResult = make(map[string]string)
-	initialModel := initialFormModel(config)
-	_, resultModelErr := tea.NewProgram(&initialModel).Run()
-	if resultModelErr != nil {
-		logz.Error("Error running form model.", map[string]interface{}{
-			"context": "ShowFormWithNotification",
-			"error":   resultModelErr,
-		})
-		return nil, resultModelErr
+func ShowFormWithNotification(config Config) (*FormResult, error) {
+	result, err := runForm(initialFormModel(config), "ShowFormWithNotification")
+	if err != nil {
+		return nil, err
 	}
 	// Display notification
-	DisplayNotification("Form submitted successfully", "info")
-	return inputResult, nil
-}
-
-func DisplayNotification(message, messageType string) {
-	// Implement the notification system logic here
-	// Use different styles and colors to differentiate between information, warnings, and errors
-	switch messageType {
-	case "info":
-		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#75FBAB")).Render(message))
-	case "warning":
-		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#FDFF90")).Render(message))
-	case "error":
-		fmt.Println(lipgloss.NewStyle().Foreground(lipgloss.Color("#FF7698")).Render(message))
-	default:
-		fmt.Println(message)
-	}
+	DisplayInfoNotification("Form submitted successfully")
+	return result, nil
 }
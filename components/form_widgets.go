@@ -0,0 +1,298 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	. "github.com/faelmori/xtui/types"
+)
+
+// optionListHeight is the number of options a focused select shows at once.
+const optionListHeight = 6
+
+// formWidget edits a field that is not typed in a text input: a toggle, a select or a multi-select.
+// The form keeps the text input of the field in sync with Value, which is what the field validates.
+type formWidget interface {
+	// Update handles a key pressed while the field is focused and reports whether it was used.
+	Update(msg tea.KeyMsg) bool
+	// View renders the widget after the prompt of the field. Lines after the first are indented.
+	View(focused bool, indent string) string
+	// Value returns the value of the widget as text.
+	Value() string
+	// Result returns the typed value of the widget, stored in the form result.
+	Result() interface{}
+}
+
+// newFormWidget returns the widget editing the field, or nil when it is edited in a text input. Bool
+// fields get a toggle, and fields with options a select or a multi-select. The options of a
+// provider are read once, when the form is created.
+func newFormWidget(field FormField) formWidget {
+	if field.Type() == FieldBool {
+		on, _ := ParseFormBool(field.Value())
+		return &toggleWidget{on: on}
+	}
+	choice, ok := field.(FormFieldOptions)
+	if !ok {
+		return nil
+	}
+	options := choice.Options()
+	if len(options) == 0 {
+		return nil
+	}
+	list := optionList{options: options, placeholder: field.Placeholder()}
+	if choice.MultiSelect() {
+		w := &multiSelectWidget{optionList: list, checked: make([]bool, len(options))}
+		for _, value := range SplitFormList(field.Value()) {
+			if i := list.indexOf(value); i >= 0 {
+				w.checked[i] = true
+			}
+		}
+		return w
+	}
+	w := &selectWidget{optionList: list, selected: list.indexOf(strings.TrimSpace(field.Value()))}
+	w.cursor = max(w.selected, 0)
+	return w
+}
+
+// toggleWidget is a checkbox, toggled with space, x or the arrows, and set with y or n.
+type toggleWidget struct {
+	on bool
+}
+
+func (w *toggleWidget) Update(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case " ", "x", "left", "right":
+		w.on = !w.on
+	case "y":
+		w.on = true
+	case "n":
+		w.on = false
+	default:
+		return false
+	}
+	return true
+}
+
+func (w *toggleWidget) View(focused bool, _ string) string {
+	box := "[ ]"
+	if w.on {
+		box = "[x]"
+	}
+	if !focused {
+		return box
+	}
+	return focusedStyle.Render(box) + helpStyle.Render("  space to toggle")
+}
+
+func (w *toggleWidget) Value() string       { return strconv.FormatBool(w.on) }
+func (w *toggleWidget) Result() interface{} { return w.on }
+
+// optionList is the list of options of a select, with the option under the cursor and the text
+// typed to jump to an option.
+type optionList struct {
+	options     []string
+	placeholder string
+	cursor      int
+	query       string
+}
+
+// indexOf returns the index of the option equal to value, or -1.
+func (l *optionList) indexOf(value string) int {
+	for i, option := range l.options {
+		if option == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// move moves the cursor by delta. It reports false when the cursor is already on the first or last
+// option, so the arrows move the focus out of the list.
+func (l *optionList) move(delta int) bool {
+	next := l.cursor + delta
+	if next < 0 || next >= len(l.options) {
+		return false
+	}
+	l.cursor, l.query = next, ""
+	return true
+}
+
+// typeAhead moves the cursor to the first option starting with query, or else containing it,
+// ignoring case. A query matching no option is dropped for the last typed text alone, so typing
+// starts a new search.
+func (l *optionList) typeAhead(typed string) {
+	for _, query := range []string{l.query + typed, typed} {
+		if i := l.find(query); i >= 0 {
+			l.cursor, l.query = i, query
+			return
+		}
+	}
+}
+
+func (l *optionList) find(query string) int {
+	query = strings.ToLower(query)
+	if query == "" {
+		return -1
+	}
+	for i, option := range l.options {
+		if strings.HasPrefix(strings.ToLower(option), query) {
+			return i
+		}
+	}
+	for i, option := range l.options {
+		if strings.Contains(strings.ToLower(option), query) {
+			return i
+		}
+	}
+	return -1
+}
+
+// updateQuery handles the keys editing the type-ahead query and reports whether msg was one.
+func (l *optionList) updateQuery(msg tea.KeyMsg) bool {
+	switch {
+	case msg.Type == tea.KeyBackspace:
+		if l.query != "" {
+			runes := []rune(l.query)
+			l.query = string(runes[:len(runes)-1])
+			if i := l.find(l.query); i >= 0 {
+				l.cursor = i
+			}
+		}
+		return true
+	case msg.Type == tea.KeyRunes && !msg.Alt:
+		l.typeAhead(string(msg.Runes))
+		return true
+	case msg.Type == tea.KeySpace && l.query != "":
+		l.typeAhead(" ")
+		return true
+	}
+	return false
+}
+
+// view renders the summary of the value and, when focused, the options around the cursor, each
+// marked by mark.
+func (l *optionList) view(focused bool, indent, summary string, mark func(i int) string, hint string) string {
+	if summary == "" {
+		summary = blurredStyle.Render(l.placeholder)
+	} else if focused {
+		summary = focusedStyle.Render(summary)
+	}
+	if !focused {
+		return summary
+	}
+
+	var b strings.Builder
+	b.WriteString(summary)
+	if l.query != "" {
+		b.WriteString(helpStyle.Render("  search: " + l.query))
+	}
+	start := min(max(l.cursor-optionListHeight/2, 0), max(len(l.options)-optionListHeight, 0))
+	end := min(start+optionListHeight, len(l.options))
+	for i := start; i < end; i++ {
+		line := "  " + mark(i) + " " + l.options[i]
+		if i == l.cursor {
+			line = focusedStyle.Render("› " + mark(i) + " " + l.options[i])
+		}
+		b.WriteString("\n" + indent + line)
+	}
+	if end-start < len(l.options) {
+		hint = fmt.Sprintf("%d/%d  %s", l.cursor+1, len(l.options), hint)
+	}
+	b.WriteString("\n" + indent + helpStyle.Render(hint))
+	return b.String()
+}
+
+// selectWidget chooses one of the options. The option under the cursor is the selected one; until
+// the cursor moves, or space is pressed, the field keeps its value, or none.
+type selectWidget struct {
+	optionList
+	selected int
+}
+
+func (w *selectWidget) Update(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "up", "down":
+		delta := 1
+		if msg.String() == "up" {
+			delta = -1
+		}
+		if !w.move(delta) {
+			return false
+		}
+	case " ":
+		// space selects the option under the cursor, unless it continues the query
+		w.updateQuery(msg)
+	default:
+		if !w.updateQuery(msg) {
+			return false
+		}
+		if w.query == "" && w.selected < 0 {
+			return true
+		}
+	}
+	w.selected = w.cursor
+	return true
+}
+
+func (w *selectWidget) View(focused bool, indent string) string {
+	mark := func(i int) string {
+		if i == w.selected {
+			return "(•)"
+		}
+		return "( )"
+	}
+	return w.view(focused, indent, w.Value(), mark, "type to search")
+}
+
+func (w *selectWidget) Value() string {
+	if w.selected < 0 {
+		return ""
+	}
+	return w.options[w.selected]
+}
+
+func (w *selectWidget) Result() interface{} { return w.Value() }
+
+// multiSelectWidget checks any number of the options with space.
+type multiSelectWidget struct {
+	optionList
+	checked []bool
+}
+
+func (w *multiSelectWidget) Update(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "up":
+		return w.move(-1)
+	case "down":
+		return w.move(1)
+	case " ":
+		w.checked[w.cursor] = !w.checked[w.cursor]
+		return true
+	}
+	return w.updateQuery(msg)
+}
+
+func (w *multiSelectWidget) View(focused bool, indent string) string {
+	mark := func(i int) string {
+		if w.checked[i] {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	return w.view(focused, indent, strings.Join(w.values(), ", "), mark, "space to check, type to search")
+}
+
+func (w *multiSelectWidget) values() []string {
+	values := []string{}
+	for i, checked := range w.checked {
+		if checked {
+			values = append(values, w.options[i])
+		}
+	}
+	return values
+}
+
+func (w *multiSelectWidget) Value() string       { return strings.Join(w.values(), ",") }
+func (w *multiSelectWidget) Result() interface{} { return w.values() }
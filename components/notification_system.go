@@ -18,10 +18,11 @@ type Notification struct {
 	Type    NotificationType
 }
 
-func DisplayNotification(notification Notification) {
+// Render returns the message styled with the color of its notification type.
+func (n Notification) Render() string {
 	var style lipgloss.Style
 
-	switch notification.Type {
+	switch n.Type {
 	case Info:
 		style = lipgloss.NewStyle().Foreground(lipgloss.Color("#75FBAB"))
 	case Warning:
@@ -32,7 +33,11 @@ func DisplayNotification(notification Notification) {
 		style = lipgloss.NewStyle()
 	}
 
-	fmt.Println(style.Render(notification.Message))
+	return style.Render(n.Message)
+}
+
+func DisplayNotification(notification Notification) {
+	fmt.Println(notification.Render())
 }
 
 func DisplayInfoNotification(message string) {
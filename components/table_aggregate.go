@@ -0,0 +1,341 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	. "github.com/faelmori/xtui/types"
+)
+
+// Aggregation summarizes the values of a column in the table footer and in group headers.
+type Aggregation string
+
+const (
+	// AggregateCount counts the non-empty cells.
+	AggregateCount Aggregation = "count"
+	// AggregateSum adds up the numeric cells; byte sizes are summed as sizes.
+	AggregateSum Aggregation = "sum"
+	// AggregateAvg averages the numeric cells.
+	AggregateAvg Aggregation = "avg"
+	// AggregateMin picks the lowest cell, compared as the column type.
+	AggregateMin Aggregation = "min"
+	// AggregateMax picks the highest cell, compared as the column type.
+	AggregateMax Aggregation = "max"
+	// AggregateDistinct counts the distinct non-empty cells.
+	AggregateDistinct Aggregation = "distinct"
+)
+
+// aggregationCycle is the order in which ctrl+b steps through the aggregations; "" removes it.
+var aggregationCycle = []Aggregation{"", AggregateCount, AggregateSum, AggregateAvg, AggregateMin, AggregateMax, AggregateDistinct}
+
+var (
+	// footerStyle draws the footer row with the column aggregations.
+	footerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#75FBAB")).Bold(true)
+	// groupHeaderStyle draws the header rows of the groups.
+	groupHeaderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#01BE85")).Bold(true)
+)
+
+// rowGroup holds the filtered rows sharing a value of the group-by column. header is the row drawn
+// for the group, with the aggregations of its rows.
+type rowGroup struct {
+	value  string
+	rows   [][]string
+	header []string
+}
+
+// aggregate computes agg over the values of a column of the given type. Empty cells are ignored,
+// and so are cells that are not numbers for sum and avg. It returns "" when nothing was aggregated.
+func aggregate(agg Aggregation, typ ColumnType, values []string) string {
+	values = slices.DeleteFunc(values, func(v string) bool { return strings.TrimSpace(v) == "" })
+	switch agg {
+	case AggregateCount:
+		return strconv.Itoa(len(values))
+	case AggregateDistinct:
+		seen := make(map[string]bool, len(values))
+		for _, v := range values {
+			seen[v] = true
+		}
+		return strconv.Itoa(len(seen))
+	case AggregateMin, AggregateMax:
+		if len(values) == 0 {
+			return ""
+		}
+		best := values[0]
+		for _, v := range values[1:] {
+			c := CompareCells(typ, v, best)
+			if (agg == AggregateMin && c < 0) || (agg == AggregateMax && c > 0) {
+				best = v
+			}
+		}
+		return best
+	case AggregateSum, AggregateAvg:
+		parse := ParseNumber
+		if typ == ColumnBytes {
+			parse = ParseByteSize
+		}
+		sum, n := 0.0, 0
+		for _, v := range values {
+			if x, err := parse(v); err == nil {
+				sum += x
+				n++
+			}
+		}
+		if n == 0 {
+			return ""
+		}
+		if agg == AggregateAvg {
+			sum /= float64(n)
+		}
+		switch {
+		case typ == ColumnBytes:
+			return FormatByteSize(sum)
+		case agg == AggregateAvg:
+			return strconv.FormatFloat(sum, 'f', 2, 64)
+		}
+		return strconv.FormatFloat(sum, 'f', -1, 64)
+	}
+	return ""
+}
+
+func columnValues(rows [][]string, col int) []string {
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		values[i] = cellAt(row, col)
+	}
+	return values
+}
+
+// ColumnAggregation returns the aggregation shown for the column, or "" when there is none.
+func (k *TableRenderer) ColumnAggregation(header string) Aggregation {
+	return k.aggregations[header]
+}
+
+// SetAggregation shows agg for the column in the footer and in the group headers. An empty
+// aggregation removes it; the footer is hidden when no column has one.
+func (k *TableRenderer) SetAggregation(header string, agg Aggregation) {
+	if !slices.Contains(k.headers, header) {
+		return
+	}
+	if agg == "" {
+		delete(k.aggregations, header)
+	} else {
+		k.aggregations[header] = agg
+	}
+	k.summarize()
+	k.invalidate()
+	k.applySize()
+}
+
+// cycleAggregation steps the aggregation of the column under the sort cursor to the next one.
+func (k *TableRenderer) cycleAggregation() {
+	if k.sortCursor >= len(k.headers) {
+		return
+	}
+	header := k.headers[k.sortCursor]
+	next := (slices.Index(aggregationCycle, k.aggregations[header]) + 1) % len(aggregationCycle)
+	k.SetAggregation(header, aggregationCycle[next])
+}
+
+// AggregateColumn computes agg over the column for every row that passes the filter.
+func (k *TableRenderer) AggregateColumn(header string, agg Aggregation) string {
+	col := slices.Index(k.headers, header)
+	if col < 0 {
+		return ""
+	}
+	var values []string
+	k.eachDataRow(func(row []string) { values = append(values, cellAt(row, col)) })
+	return aggregate(agg, k.ColumnType(header), values)
+}
+
+// GroupBy returns the header of the column the rows are grouped by, or "" when they are not.
+func (k *TableRenderer) GroupBy() string {
+	return k.groupBy
+}
+
+// SetGroupBy groups the filtered rows by the values of the column, each group collapsed under a
+// header row. An empty or unknown header turns grouping off.
+func (k *TableRenderer) SetGroupBy(header string) {
+	if !slices.Contains(k.headers, header) {
+		header = ""
+	}
+	k.groupBy = header
+	clear(k.expanded)
+	k.selectedRow = -1
+	k.page = 0
+	k.SortRows()
+}
+
+// ToggleGroup expands the group whose header is the filtered row at index i, or collapses it
+// when it is expanded. It reports whether the row is a group header.
+func (k *TableRenderer) ToggleGroup(i int) bool {
+	g := k.groupAt(i)
+	if g == nil {
+		return false
+	}
+	k.expanded[g.value] = !k.expanded[g.value]
+	k.layoutGroups()
+	k.invalidate()
+	return true
+}
+
+// groupAt returns the group whose header is the filtered row at index i, or nil.
+func (k *TableRenderer) groupAt(i int) *rowGroup {
+	row := k.rowAt(i)
+	if len(row) == 0 {
+		return nil
+	}
+	return k.groupHeaders[&row[0]]
+}
+
+// isGroupHeader reports whether the row is the header of a group rather than a data row.
+func (k *TableRenderer) isGroupHeader(row []string) bool {
+	return len(row) > 0 && k.groupHeaders[&row[0]] != nil
+}
+
+// eachDataRow calls fn with every row that passes the filter, in display order, including the rows
+// of collapsed groups and leaving out the group headers. Providers are read in chunks.
+func (k *TableRenderer) eachDataRow(fn func(row []string)) {
+	if k.groupBy != "" {
+		for _, g := range k.groups {
+			for _, row := range g.rows {
+				fn(row)
+			}
+		}
+		return
+	}
+	for offset, total := 0, k.RowCount(); offset < total; offset += providerChunkSize {
+		for _, row := range k.RowsAt(offset, providerChunkSize) {
+			fn(row)
+		}
+	}
+}
+
+// dataRows returns every row that passes the filter, in display order, without group headers.
+func (k *TableRenderer) dataRows() [][]string {
+	var rows [][]string
+	k.eachDataRow(func(row []string) { rows = append(rows, row) })
+	return rows
+}
+
+// summarize rebuilds the groups and the footer from the filtered rows, widening the columns to
+// fit them.
+func (k *TableRenderer) summarize() {
+	k.groups = nil
+	clear(k.groupHeaders)
+	if col := slices.Index(k.headers, k.groupBy); col >= 0 {
+		k.buildGroups(col)
+	} else {
+		k.groupBy = ""
+		k.groupedRows = nil
+	}
+
+	k.footer = nil
+	if len(k.aggregations) > 0 {
+		values := make([][]string, len(k.headers))
+		k.eachDataRow(func(row []string) {
+			for col, header := range k.headers {
+				if _, ok := k.aggregations[header]; ok {
+					values[col] = append(values[col], cellAt(row, col))
+				}
+			}
+		})
+		k.footer = make([]string, len(k.headers))
+		for col, header := range k.headers {
+			if agg, ok := k.aggregations[header]; ok {
+				k.footer[col] = fmt.Sprintf("%s: %s", agg, aggregate(agg, k.ColumnType(header), values[col]))
+			}
+		}
+		k.fitColumnsTo(k.footer)
+	}
+}
+
+// buildGroups groups the filtered rows by the values of column col. Groups are ordered by their
+// value, in the direction of the column in the sort stack, and rows keep their order in a group.
+func (k *TableRenderer) buildGroups(col int) {
+	index := make(map[string]*rowGroup)
+	for _, row := range k.filteredRows {
+		value := cellAt(row, col)
+		g, ok := index[value]
+		if !ok {
+			g = &rowGroup{value: value}
+			index[value] = g
+			k.groups = append(k.groups, g)
+		}
+		g.rows = append(g.rows, row)
+	}
+
+	asc := true
+	if i := k.sortKeyIndex(k.groupBy); i >= 0 {
+		asc = k.sortKeys[i].Asc
+	}
+	typ := k.ColumnType(k.groupBy)
+	slices.SortStableFunc(k.groups, func(a, b *rowGroup) int {
+		c := CompareCells(typ, a.value, b.value)
+		if !asc {
+			return -c
+		}
+		return c
+	})
+
+	for _, g := range k.groups {
+		g.header = make([]string, len(k.headers))
+		for i, header := range k.headers {
+			if agg, ok := k.aggregations[header]; ok && i != col {
+				g.header[i] = aggregate(agg, k.ColumnType(header), columnValues(g.rows, i))
+			}
+		}
+		k.groupHeaders[&g.header[0]] = g
+	}
+	k.layoutGroups()
+	for _, g := range k.groups {
+		k.fitColumnsTo(g.header)
+	}
+}
+
+// layoutGroups lists the group headers, each followed by its rows when the group is expanded.
+func (k *TableRenderer) layoutGroups() {
+	col := slices.Index(k.headers, k.groupBy)
+	k.groupedRows = make([][]string, 0, len(k.groups))
+	for _, g := range k.groups {
+		marker, value := "▸", g.value
+		if k.expanded[g.value] {
+			marker = "▾"
+		}
+		if value == "" {
+			value = "(empty)"
+		}
+		g.header[col] = fmt.Sprintf("%s %s (%d)", marker, value, len(g.rows))
+		k.groupedRows = append(k.groupedRows, g.header)
+		if k.expanded[g.value] {
+			k.groupedRows = append(k.groupedRows, g.rows...)
+		}
+	}
+}
+
+// fitColumnsTo widens the columns to the cells of row, up to the maximum column width.
+func (k *TableRenderer) fitColumnsTo(row []string) {
+	for i, cell := range row {
+		if i < len(k.colWidths) {
+			k.colWidths[i] = min(max(k.colWidths[i], lipgloss.Width(cell)), maxColumnWidth)
+		}
+	}
+}
+
+// footerLines returns how many screen lines the footer row takes.
+func (k *TableRenderer) footerLines() int {
+	if k.footer == nil {
+		return 0
+	}
+	return 1
+}
+
+// groupStatus describes the grouping for the footer, or "" when the rows are not grouped.
+func (k *TableRenderer) groupStatus() string {
+	if k.groupBy == "" {
+		return ""
+	}
+	return fmt.Sprintf("  Grouped by %s: %d groups (space: expand)", k.groupBy, len(k.groups))
+}
@@ -0,0 +1,297 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// columnPicker is the state of the column chooser overlay.
+type columnPicker struct {
+	cursor int
+}
+
+// VisibleColumns returns the headers of the visible columns, in display order.
+func (k *TableRenderer) VisibleColumns() []string {
+	var columns []string
+	for _, header := range k.columnOrder {
+		if k.visibleCols[header] {
+			columns = append(columns, header)
+		}
+	}
+	return columns
+}
+
+// ColumnOrder returns every header, visible or not, in display order.
+func (k *TableRenderer) ColumnOrder() []string {
+	return slices.Clone(k.columnOrder)
+}
+
+// SetColumnOrder changes the display order. Unknown headers are ignored and headers left out keep
+// their relative order after the given ones.
+func (k *TableRenderer) SetColumnOrder(order []string) {
+	columns := make([]string, 0, len(k.headers))
+	for _, header := range order {
+		if slices.Contains(k.headers, header) && !slices.Contains(columns, header) {
+			columns = append(columns, header)
+		}
+	}
+	for _, header := range k.columnOrder {
+		if !slices.Contains(columns, header) {
+			columns = append(columns, header)
+		}
+	}
+	k.columnOrder = columns
+	k.relayout()
+}
+
+// MoveColumn moves the column by delta positions in the display order.
+func (k *TableRenderer) MoveColumn(header string, delta int) {
+	from := slices.Index(k.columnOrder, header)
+	if from < 0 {
+		return
+	}
+	to := min(max(from+delta, 0), len(k.columnOrder)-1)
+	k.columnOrder = slices.Insert(slices.Delete(k.columnOrder, from, from+1), to, header)
+	k.relayout()
+}
+
+// SetColumnVisible shows or hides a column. The last visible column cannot be hidden.
+func (k *TableRenderer) SetColumnVisible(header string, visible bool) {
+	if _, ok := k.visibleCols[header]; !ok {
+		return
+	}
+	if !visible && k.visibleCols[header] && len(k.VisibleColumns()) == 1 {
+		return
+	}
+	k.visibleCols[header] = visible
+	k.relayout()
+}
+
+// ToggleColumn shows the column when it is hidden and hides it otherwise.
+func (k *TableRenderer) ToggleColumn(header string) {
+	k.SetColumnVisible(header, !k.visibleCols[header])
+}
+
+// ToggleColumnVisibility inverts the visibility of every column, showing the hidden ones and hiding
+// the others. When every column is visible, they stay shown, as a table needs a visible column.
+func (k *TableRenderer) ToggleColumnVisibility() {
+	anyHidden := false
+	for _, visible := range k.visibleCols {
+		anyHidden = anyHidden || !visible
+	}
+	for header := range k.visibleCols {
+		k.visibleCols[header] = !anyHidden || !k.visibleCols[header]
+	}
+	k.relayout()
+}
+
+// PinnedColumns returns how many leading columns of the display order stay in place while the
+// table is scrolled horizontally.
+func (k *TableRenderer) PinnedColumns() int {
+	return k.pinnedCols
+}
+
+// SetPinnedColumns pins the first n columns of the display order.
+func (k *TableRenderer) SetPinnedColumns(n int) {
+	k.pinnedCols = min(max(n, 0), len(k.columnOrder))
+	k.relayout()
+}
+
+// ScrollColumns scrolls the unpinned columns by delta columns.
+func (k *TableRenderer) ScrollColumns(delta int) {
+	if delta > 0 && !k.moreColumnsRight() {
+		return
+	}
+	k.colOffset = max(k.colOffset+delta, 0)
+	k.relayout()
+}
+
+// syncColumnOrder keeps the display order in line with the headers after they changed: known
+// columns keep their place, removed ones are dropped and new ones are appended and shown.
+func (k *TableRenderer) syncColumnOrder() {
+	order := slices.DeleteFunc(slices.Clone(k.columnOrder), func(header string) bool {
+		return !slices.Contains(k.headers, header)
+	})
+	for _, header := range k.headers {
+		if !slices.Contains(order, header) {
+			order = append(order, header)
+		}
+		if _, ok := k.visibleCols[header]; !ok {
+			k.visibleCols[header] = true
+		}
+	}
+	k.columnOrder = order
+	k.pinnedCols = min(k.pinnedCols, len(order))
+}
+
+// relayout recomputes the drawn columns and reloads headers and rows into the lipgloss table.
+func (k *TableRenderer) relayout() {
+	k.syncHeaders()
+	k.syncTableRows()
+}
+
+// layoutColumns picks the columns drawn on screen: the visible pinned columns first, then the
+// unpinned ones from the horizontal scroll offset, as many as fit the screen width. At least one
+// unpinned column is always drawn. The rows are reloaded when the selection changed.
+func (k *TableRenderer) layoutColumns() {
+	var pinned, scrollable []int
+	for pos, header := range k.columnOrder {
+		col := slices.Index(k.headers, header)
+		if col < 0 || !k.visibleCols[header] {
+			continue
+		}
+		if pos < k.pinnedCols {
+			pinned = append(pinned, col)
+		} else {
+			scrollable = append(scrollable, col)
+		}
+	}
+	k.colOffset = min(k.colOffset, max(len(scrollable)-1, 0))
+
+	// every column takes its content, one space of padding on each side and its right border
+	columnSpace := func(col int) int { return k.columnWidth(col) + 3 }
+	used := 1
+	rendered := slices.Clone(pinned)
+	for _, col := range pinned {
+		used += columnSpace(col)
+	}
+	k.colsRight = 0
+	for i, col := range scrollable[k.colOffset:] {
+		if k.tableWidth() > 0 && i > 0 && used+columnSpace(col) > k.tableWidth() {
+			k.colsRight = len(scrollable) - k.colOffset - i
+			break
+		}
+		rendered = append(rendered, col)
+		used += columnSpace(col)
+	}
+
+	if !slices.Equal(rendered, k.renderedCols) {
+		k.renderedCols = rendered
+		k.invalidate()
+	}
+}
+
+// moreColumnsRight reports whether visible columns are cut off on the right.
+func (k *TableRenderer) moreColumnsRight() bool {
+	return k.colsRight > 0
+}
+
+// renderedColumn returns the header index of the drawn column col, or -1.
+func (k *TableRenderer) renderedColumn(col int) int {
+	if col < 0 || col >= len(k.renderedCols) {
+		return -1
+	}
+	return k.renderedCols[col]
+}
+
+// moveSortCursor moves the sort cursor to the next visible column in display order, scrolling it
+// into view.
+func (k *TableRenderer) moveSortCursor() {
+	visible := k.VisibleColumns()
+	if len(visible) == 0 {
+		return
+	}
+	current := -1
+	if k.sortCursor < len(k.headers) {
+		current = slices.Index(visible, k.headers[k.sortCursor])
+	}
+	next := visible[(current+1)%len(visible)]
+	k.sortCursor = slices.Index(k.headers, next)
+
+	if pos := slices.Index(k.columnOrder, next); pos >= k.pinnedCols {
+		scrollPos := 0
+		for _, header := range k.columnOrder[k.pinnedCols:pos] {
+			if k.visibleCols[header] {
+				scrollPos++
+			}
+		}
+		if scrollPos < k.colOffset {
+			k.colOffset = scrollPos
+		}
+		for k.layoutColumns(); !slices.Contains(k.renderedCols, k.sortCursor) && k.moreColumnsRight(); k.layoutColumns() {
+			k.colOffset++
+		}
+	}
+	k.relayout()
+}
+
+// columnsStatus describes the horizontal scroll position for the footer, or "" when every visible
+// column is drawn.
+func (k *TableRenderer) columnsStatus() string {
+	visible := len(k.VisibleColumns())
+	if k.colOffset == 0 && k.colsRight == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  Columns: %d/%d (shift+left/right)", len(k.renderedCols), visible)
+}
+
+// OpenColumnPicker shows the column chooser overlay.
+func (k *TableRenderer) OpenColumnPicker() {
+	k.columnPicker = &columnPicker{}
+}
+
+// updateColumnPicker handles a key while the column chooser is open.
+func (k *TableRenderer) updateColumnPicker(msg tea.KeyMsg) {
+	p := k.columnPicker
+	if len(k.columnOrder) == 0 {
+		k.columnPicker = nil
+		return
+	}
+	p.cursor = min(p.cursor, len(k.columnOrder)-1)
+	header := k.columnOrder[p.cursor]
+	switch msg.String() {
+	case "esc", "enter", "ctrl+g":
+		k.columnPicker = nil
+	case "up":
+		p.cursor = max(p.cursor-1, 0)
+	case "down":
+		p.cursor = min(p.cursor+1, len(k.columnOrder)-1)
+	case " ", "x":
+		k.ToggleColumn(header)
+	case "shift+up", "K":
+		k.MoveColumn(header, -1)
+		p.cursor = slices.Index(k.columnOrder, header)
+	case "shift+down", "J":
+		k.MoveColumn(header, 1)
+		p.cursor = slices.Index(k.columnOrder, header)
+	case "p":
+		if p.cursor < k.pinnedCols {
+			k.SetPinnedColumns(p.cursor)
+		} else {
+			k.SetPinnedColumns(p.cursor + 1)
+		}
+	case "a":
+		for _, h := range k.columnOrder {
+			k.visibleCols[h] = true
+		}
+		k.relayout()
+	}
+}
+
+// columnPickerView renders the column chooser overlay.
+func (k *TableRenderer) columnPickerView() string {
+	p := k.columnPicker
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render("Columns") + "\n\n")
+	for i, header := range k.columnOrder {
+		check := "[ ]"
+		if k.visibleCols[header] {
+			check = "[x]"
+		}
+		line := check + " " + header
+		if i < k.pinnedCols {
+			line += " (pinned)"
+		}
+		if i == p.cursor {
+			b.WriteString(focusedStyle.Render("> "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	b.WriteString("\n" + helpStyle.Render("space: show/hide • shift+up/down: move • p: pin up to here\na: show all • esc: close"))
+	return k.overlay(b.String())
+}
@@ -0,0 +1,275 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DetailPosition places the row detail pane next to the table.
+type DetailPosition int
+
+const (
+	// DetailHidden shows no detail pane.
+	DetailHidden DetailPosition = iota
+	// DetailRight shows the detail pane on the right of the table.
+	DetailRight
+	// DetailBottom shows the detail pane below the table.
+	DetailBottom
+)
+
+const (
+	// detailMinWidth is the narrowest detail pane on the right, in columns.
+	detailMinWidth = 30
+	// detailMinHeight is the lowest detail pane at the bottom, in lines.
+	detailMinHeight = 8
+	// detailScrollStep is how many lines pgup and pgdown scroll a field pane.
+	detailScrollStep = 5
+)
+
+// fieldPane is the scroll state of a view listing the fields of the selected row.
+type fieldPane struct {
+	cursor int
+	scroll int
+	// follow asks the next render to scroll the field under the cursor into view.
+	follow bool
+}
+
+// SetDetailPane shows the detail pane at the given position, or hides it.
+func (k *TableRenderer) SetDetailPane(pos DetailPosition) {
+	k.detailPos = pos
+	if pos == DetailHidden {
+		k.detailFocused = false
+	}
+	k.applySize()
+}
+
+// OpenRecordView shows the selected row full screen, selecting the first row when none is.
+func (k *TableRenderer) OpenRecordView() tea.Cmd {
+	if k.RowCount() == 0 {
+		return k.notify(Warning, "No rows to show")
+	}
+	if k.selectedRow < 0 {
+		_ = k.RowsNavigate("down")
+	}
+	k.record = &fieldPane{}
+	return nil
+}
+
+// applySize fits the table, and the detail pane, to the last known screen size.
+func (k *TableRenderer) applySize() {
+	if k.width > 0 && k.height > 0 {
+		k.kTb = k.kTb.Width(k.tableWidth())
+		k.kTb = k.kTb.Height(k.height - k.detailHeight())
+		if pageSize := k.height - tableChromeLines - k.detailHeight() - k.footerLines(); pageSize > 0 {
+			if k.pageLimit > 0 {
+				pageSize = min(pageSize, k.pageLimit)
+			}
+			k.page = k.page * k.pageSize / pageSize
+			k.pageSize = pageSize
+			if k.selectedRow >= 0 {
+				k.page = k.selectedRow / k.pageSize
+			}
+		}
+	} else if k.pageLimit > 0 {
+		k.pageSize = k.pageLimit
+	}
+	k.relayout()
+}
+
+// tableWidth returns the screen width left for the table, or 0 when the size is unknown.
+func (k *TableRenderer) tableWidth() int {
+	if k.detailPos == DetailRight && k.width > 0 {
+		return k.width - k.detailWidth()
+	}
+	return k.width
+}
+
+func (k *TableRenderer) detailWidth() int {
+	if k.width <= 0 {
+		return detailMinWidth + 10
+	}
+	return max(min(max(detailMinWidth, k.width*2/5), k.width-20), 10)
+}
+
+func (k *TableRenderer) detailHeight() int {
+	if k.detailPos != DetailBottom {
+		return 0
+	}
+	return max(detailMinHeight, k.height/3)
+}
+
+// updateDetailPane handles a key while the detail pane has the focus.
+func (k *TableRenderer) updateDetailPane(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "tab", "esc":
+		k.detailFocused = false
+		return nil
+	case "ctrl+d":
+		k.SetDetailPane((k.detailPos + 1) % 3)
+		return nil
+	}
+	_, cmd := k.updateFieldPane(&k.detail, msg)
+	return cmd
+}
+
+// updateRecordView handles a key while the record view is open.
+func (k *TableRenderer) updateRecordView(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "q", "ctrl+f":
+		k.record = nil
+	case "right", "n":
+		_ = k.RowsNavigate("down")
+		k.record.scroll = 0
+	case "left", "p":
+		_ = k.RowsNavigate("up")
+		k.record.scroll = 0
+	default:
+		_, cmd := k.updateFieldPane(k.record, msg)
+		return cmd
+	}
+	return nil
+}
+
+// updateFieldPane handles the keys shared by the detail pane and the record view and reports
+// whether the key was used.
+func (k *TableRenderer) updateFieldPane(p *fieldPane, msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch msg.String() {
+	case "up":
+		p.cursor = max(p.cursor-1, 0)
+		p.follow = true
+	case "down":
+		p.cursor = min(p.cursor+1, len(k.columnOrder)-1)
+		p.follow = true
+	case "pgup":
+		p.scroll = max(p.scroll-detailScrollStep, 0)
+	case "pgdown":
+		p.scroll += detailScrollStep
+	case "enter", "c":
+		return true, k.copyField(p.cursor)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// copyField copies the value of the field at position i of the display order of the selected row.
+func (k *TableRenderer) copyField(i int) tea.Cmd {
+	row := k.rowAt(k.selectedRow)
+	if row == nil || i < 0 || i >= len(k.columnOrder) {
+		return nil
+	}
+	header := k.columnOrder[i]
+	if err := clipboard.WriteAll(cellAt(row, slices.Index(k.headers, header))); err != nil {
+		return k.notify(Error, "Copy failed: "+err.Error())
+	}
+	return k.notify(Info, "Copied "+header)
+}
+
+// fieldLines renders the row as key/value pairs, in display order and with hidden columns
+// included, wrapping values to width. It returns the lines and the first line of every field.
+func (k *TableRenderer) fieldLines(row []string, width, cursor int, focused bool) ([]string, []int) {
+	keyWidth := 0
+	for _, header := range k.columnOrder {
+		keyWidth = max(keyWidth, lipgloss.Width(header))
+	}
+	keyWidth = max(min(keyWidth, width/3), 1)
+	valueStyle := lipgloss.NewStyle().Width(max(width-keyWidth-3, 8))
+
+	var lines []string
+	starts := make([]int, 0, len(k.columnOrder))
+	for i, header := range k.columnOrder {
+		starts = append(starts, len(lines))
+		marker, keyStyle := "  ", lipgloss.NewStyle().Bold(true)
+		if focused && i == cursor {
+			marker, keyStyle = "> ", focusedStyle.Bold(true)
+		}
+		value := strings.ReplaceAll(cellAt(row, slices.Index(k.headers, header)), "\t", " ")
+		for j, line := range strings.Split(valueStyle.Render(value), "\n") {
+			if j == 0 {
+				lines = append(lines, marker+keyStyle.Render(fitCell(header, keyWidth))+" "+line)
+			} else {
+				lines = append(lines, strings.Repeat(" ", keyWidth+3)+line)
+			}
+		}
+	}
+	return lines, starts
+}
+
+// fieldPaneView renders the fields of the selected row in a bordered box of the given size,
+// scrolled as p says.
+func (k *TableRenderer) fieldPaneView(p *fieldPane, title string, width, height int, focused bool) string {
+	innerWidth := max(width-4, 10)
+	bodyHeight := max(height-3, 1)
+
+	var body []string
+	if row := k.rowAt(k.selectedRow); row == nil {
+		body = []string{helpStyle.Render("Select a row with up/down")}
+	} else {
+		lines, starts := k.fieldLines(row, innerWidth, p.cursor, focused)
+		if p.follow && p.cursor < len(starts) {
+			start, end := starts[p.cursor], len(lines)
+			if p.cursor+1 < len(starts) {
+				end = starts[p.cursor+1]
+			}
+			if start < p.scroll {
+				p.scroll = start
+			} else if end > p.scroll+bodyHeight {
+				p.scroll = min(start, end-bodyHeight)
+			}
+			p.follow = false
+		}
+		p.scroll = max(min(p.scroll, len(lines)-bodyHeight), 0)
+		body = lines[p.scroll:min(p.scroll+bodyHeight, len(lines))]
+	}
+
+	border := lipgloss.Color("238")
+	if focused {
+		border = lipgloss.Color("#01BE85")
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Padding(0, 1).
+		Width(max(width-2, 1)).
+		Height(max(height-2, 1)).
+		Render(lipgloss.NewStyle().Bold(true).Render(title) + "\n" + strings.Join(body, "\n"))
+}
+
+// withDetailPane places the detail pane next to the rendered table, when it is shown.
+func (k *TableRenderer) withDetailPane(tableView string) string {
+	title := "Details"
+	if k.selectedRow >= 0 {
+		title = fmt.Sprintf("Details (%d/%d)", k.selectedRow+1, k.RowCount())
+	}
+	switch k.detailPos {
+	case DetailRight:
+		pane := k.fieldPaneView(&k.detail, title, k.detailWidth(), max(lipgloss.Height(tableView), detailMinHeight), k.detailFocused)
+		return lipgloss.JoinHorizontal(lipgloss.Top, tableView, pane)
+	case DetailBottom:
+		width := k.width
+		if width <= 0 {
+			width = lipgloss.Width(tableView)
+		}
+		return tableView + "\n" + k.fieldPaneView(&k.detail, title, width, k.detailHeight(), k.detailFocused)
+	}
+	return tableView
+}
+
+// recordView renders the selected row full screen.
+func (k *TableRenderer) recordView() string {
+	width, height := k.width, k.height
+	if width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+	title := fmt.Sprintf("%s — record %d of %d", k.config.Title, k.selectedRow+1, k.RowCount())
+	if k.config.Title == "" {
+		title = fmt.Sprintf("Record %d of %d", k.selectedRow+1, k.RowCount())
+	}
+	return k.fieldPaneView(k.record, title, width, height-1, true) + "\n" +
+		helpStyle.Render("n/p: next/previous • up/down: field • c: copy • pgup/pgdown: scroll • esc: close")
+}
@@ -0,0 +1,384 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/faelmori/logz"
+	. "github.com/faelmori/xtui/types"
+)
+
+// DiffStatus tells how a row of a table diff differs between the two tables.
+type DiffStatus string
+
+const (
+	// DiffSame marks a row equal in both tables.
+	DiffSame DiffStatus = "same"
+	// DiffAdded marks a row found only in the right table.
+	DiffAdded DiffStatus = "added"
+	// DiffRemoved marks a row found only in the left table.
+	DiffRemoved DiffStatus = "removed"
+	// DiffChanged marks a row found in both tables with different cells.
+	DiffChanged DiffStatus = "changed"
+)
+
+// diffStatusColumn is the header of the column holding the DiffStatus of every row, and
+// diffStatusFallback its header when the key column is already named so.
+const (
+	diffStatusColumn   = "Diff"
+	diffStatusFallback = "Status"
+)
+
+// DiffRow is a key found in either table, with its cells on each side. Left and Right follow the
+// columns of the diff and are nil when the key is missing on that side.
+type DiffRow struct {
+	Key         string
+	Status      DiffStatus
+	Left, Right []string
+}
+
+// TableDiff aligns the rows of two tables by a key column. It is a TableDataHandler whose rows show
+// the status, the key and the cells of both tables side by side, so it can be shown and exported
+// like any table.
+type TableDiff struct {
+	Title string
+	// Key is the header of the column matching the rows of both tables.
+	Key string
+	// LeftName and RightName label the columns of each table. When they are equal, the columns are
+	// labeled "left" and "right" instead.
+	LeftName, RightName string
+	// Columns are the compared columns, other than the key: those of the left table, followed by
+	// those found only in the right one.
+	Columns []string
+	Rows    []DiffRow
+
+	// shared tells the columns found in both tables; only those are compared
+	shared map[string]bool
+	types  map[string]ColumnType
+}
+
+// DiffTables compares two tables row by row, matching the rows whose key column holds the same
+// value. Repeated keys are matched in the order they appear, and columns found in only one of the
+// tables are shown but not compared. The rows keep the order of the left
+// table, with the rows found only in the right table placed after the row they follow there.
+func DiffTables(left, right TableDataHandler, key string) (*TableDiff, error) {
+	leftHeaders, rightHeaders := left.GetHeaders(), right.GetHeaders()
+	leftKey, rightKey := slices.Index(leftHeaders, key), slices.Index(rightHeaders, key)
+	if leftKey < 0 || rightKey < 0 {
+		return nil, fmt.Errorf("key column %q is missing from one of the tables", key)
+	}
+
+	d := &TableDiff{Key: key, LeftName: "left", RightName: "right", shared: make(map[string]bool), types: make(map[string]ColumnType)}
+	for _, header := range slices.Concat(leftHeaders, rightHeaders) {
+		if header != key && !slices.Contains(d.Columns, header) {
+			d.Columns = append(d.Columns, header)
+			d.shared[header] = slices.Contains(leftHeaders, header) && slices.Contains(rightHeaders, header)
+		}
+	}
+	for _, handler := range []TableDataHandler{right, left} {
+		if typer, ok := handler.(TableColumnTyper); ok {
+			for header, typ := range typer.GetColumnTypes() {
+				d.types[header] = typ
+			}
+		}
+	}
+	if titler, ok := left.(TableTitler); ok {
+		d.Title = titler.GetTitle()
+	}
+
+	leftSource, rightSource := left.GetRows(), right.GetRows()
+	leftRows, rightRows := d.project(leftHeaders, leftSource), d.project(rightHeaders, rightSource)
+	leftKeys, rightKeys := occurrenceKeys(leftSource, leftKey), occurrenceKeys(rightSource, rightKey)
+
+	rightIndex := make(map[string]int, len(rightKeys))
+	for i, k := range rightKeys {
+		rightIndex[k] = i
+	}
+	matched := make([]bool, len(rightRows))
+	leftIndex := make(map[string]int, len(leftKeys))
+	for i, k := range leftKeys {
+		leftIndex[k] = i
+		if j, ok := rightIndex[k]; ok {
+			matched[j] = true
+		}
+	}
+
+	// the right rows following each matched row, or leading the table, are added after it
+	added := make(map[int][]int)
+	prev := -1
+	for j, k := range rightKeys {
+		if matched[j] {
+			prev = leftIndex[k]
+			continue
+		}
+		added[prev] = append(added[prev], j)
+	}
+
+	addRows := func(after int) {
+		for _, j := range added[after] {
+			d.Rows = append(d.Rows, DiffRow{Key: cellAt(rightSource[j], rightKey), Status: DiffAdded, Right: rightRows[j]})
+		}
+	}
+	addRows(-1)
+	for i, k := range leftKeys {
+		row := DiffRow{Key: cellAt(leftSource[i], leftKey), Status: DiffRemoved, Left: leftRows[i]}
+		if j, ok := rightIndex[k]; ok {
+			row.Right = rightRows[j]
+			row.Status = DiffSame
+			if d.differs(row.Left, row.Right) {
+				row.Status = DiffChanged
+			}
+		}
+		d.Rows = append(d.Rows, row)
+		addRows(i)
+	}
+	return d, nil
+}
+
+// differs reports whether the cells of a row differ in a column found in both tables.
+func (d *TableDiff) differs(left, right []string) bool {
+	for i, column := range d.Columns {
+		if d.shared[column] && cellAt(left, i) != cellAt(right, i) {
+			return true
+		}
+	}
+	return false
+}
+
+// project returns the cells of the rows in the order of the diff columns.
+func (d *TableDiff) project(headers []string, rows [][]string) [][]string {
+	cols := make([]int, len(d.Columns))
+	for i, column := range d.Columns {
+		cols[i] = slices.Index(headers, column)
+	}
+	projected := make([][]string, len(rows))
+	for i, row := range rows {
+		projected[i] = make([]string, len(cols))
+		for j, col := range cols {
+			projected[i][j] = cellAt(row, col)
+		}
+	}
+	return projected
+}
+
+// occurrenceKeys returns the key of every row, numbered from the second time it appears, so
+// repeated keys are matched in order.
+func occurrenceKeys(rows [][]string, col int) []string {
+	seen := make(map[string]int, len(rows))
+	keys := make([]string, len(rows))
+	for i, row := range rows {
+		value := cellAt(row, col)
+		keys[i] = value
+		if n := seen[value]; n > 0 {
+			keys[i] = fmt.Sprintf("%s\x00%d", value, n)
+		}
+		seen[value]++
+	}
+	return keys
+}
+
+// OnlyDifferences returns a copy of the diff without the rows equal in both tables.
+func (d *TableDiff) OnlyDifferences() *TableDiff {
+	only := *d
+	only.Rows = slices.DeleteFunc(slices.Clone(d.Rows), func(row DiffRow) bool { return row.Status == DiffSame })
+	return &only
+}
+
+// Counts returns how many rows have each status.
+func (d *TableDiff) Counts() map[DiffStatus]int {
+	counts := make(map[DiffStatus]int, 4)
+	for _, row := range d.Rows {
+		counts[row.Status]++
+	}
+	return counts
+}
+
+// sideHeader is the header of a compared column on one side of the diff.
+func sideHeader(column, side string) string {
+	return fmt.Sprintf("%s (%s)", column, side)
+}
+
+// sides returns the labels of the left and right columns. Equal labels would give both sides the
+// same headers, so "left" and "right" are used instead.
+func (d *TableDiff) sides() (left, right string) {
+	if d.LeftName == "" || d.LeftName == d.RightName {
+		return "left", "right"
+	}
+	return d.LeftName, d.RightName
+}
+
+// statusColumn returns the header of the status column, which must not be the key column.
+func (d *TableDiff) statusColumn() string {
+	if d.Key == diffStatusColumn {
+		return diffStatusFallback
+	}
+	return diffStatusColumn
+}
+
+// onlyDifferencesFilter returns the filter that hides the rows equal in both tables.
+func (d *TableDiff) onlyDifferencesFilter() string {
+	return d.statusColumn() + "!=" + string(DiffSame)
+}
+
+// GetHeaders returns the status and key columns, followed by the columns of the left table and
+// those of the right table.
+func (d *TableDiff) GetHeaders() []string {
+	headers := []string{d.statusColumn(), d.Key}
+	left, right := d.sides()
+	for _, side := range []string{left, right} {
+		for _, column := range d.Columns {
+			headers = append(headers, sideHeader(column, side))
+		}
+	}
+	return headers
+}
+
+func (d *TableDiff) GetRows() [][]string {
+	rows := make([][]string, len(d.Rows))
+	for i, row := range d.Rows {
+		cells := make([]string, 2, 2+2*len(d.Columns))
+		cells[0], cells[1] = string(row.Status), row.Key
+		for _, side := range [][]string{row.Left, row.Right} {
+			if side == nil {
+				side = make([]string, len(d.Columns))
+			}
+			cells = append(cells, side...)
+		}
+		rows[i] = cells
+	}
+	return rows
+}
+
+func (d *TableDiff) GetColumnTypes() map[string]ColumnType {
+	hints := make(map[string]ColumnType, 1+2*len(d.Columns))
+	if typ, ok := d.types[d.Key]; ok {
+		hints[d.Key] = typ
+	}
+	left, right := d.sides()
+	for _, column := range d.Columns {
+		if typ, ok := d.types[column]; ok {
+			hints[sideHeader(column, left)] = typ
+			hints[sideHeader(column, right)] = typ
+		}
+	}
+	return hints
+}
+
+func (d *TableDiff) GetTitle() string { return d.Title }
+
+// diffStyleRules color the rows by status and highlight the cells that changed on both sides.
+func (d *TableDiff) diffStyleRules() []StyleRule {
+	status := d.statusColumn()
+	rules := []StyleRule{
+		{When: status + "=" + string(DiffAdded), Style: CellStyle{Foreground: "#75FBAB"}},
+		{When: status + "=" + string(DiffRemoved), Style: CellStyle{Foreground: "#FF7698"}},
+		{When: status + "=" + string(DiffChanged), Style: CellStyle{Foreground: "#FDFF90"}},
+		{When: status + "=" + string(DiffSame), Style: CellStyle{Faint: true}},
+	}
+	sideLeft, sideRight := d.sides()
+	offset := 2
+	for i, column := range d.Columns {
+		if !d.shared[column] {
+			continue
+		}
+		left, right := offset+i, offset+len(d.Columns)+i
+		changed := func(row []string) bool {
+			return cellAt(row, 0) == string(DiffChanged) && cellAt(row, left) != cellAt(row, right)
+		}
+		for _, side := range []string{sideLeft, sideRight} {
+			rules = append(rules, StyleRule{
+				Match:    changed,
+				Column:   sideHeader(column, side),
+				Style:    CellStyle{Foreground: "#FDFF90", Background: "#5C4A00", Bold: true},
+				Priority: 10,
+			})
+		}
+	}
+	return rules
+}
+
+// DiffOptions holds the settings of a table diff screen.
+type DiffOptions struct {
+	TableOptions
+	// LeftName and RightName label the columns of each table, such as two host names.
+	LeftName, RightName string
+	// OnlyDifferences starts with the rows equal in both tables hidden.
+	OnlyDifferences bool
+}
+
+// NewTableDiffRenderer creates a table showing the diff. ctrl+v hides or shows the rows that are
+// equal in both tables, and the export dialog writes the rows shown.
+func NewTableDiffRenderer(diff *TableDiff, opts DiffOptions) *TableRenderer {
+	if opts.LeftName != "" {
+		diff.LeftName = opts.LeftName
+	}
+	if opts.RightName != "" {
+		diff.RightName = opts.RightName
+	}
+	if opts.Title == "" {
+		opts.Title = diff.Title
+	}
+	tableOpts := opts.TableOptions
+	tableOpts.StyleRules = append(diff.diffStyleRules(), opts.StyleRules...)
+	tableOpts.ColumnTypes = diff.GetColumnTypes()
+	for header, typ := range opts.ColumnTypes {
+		tableOpts.ColumnTypes[header] = typ
+	}
+
+	k := NewTableRendererFromHandler(diff, tableOpts)
+	k.diff = diff
+	if opts.OnlyDifferences {
+		k.ShowOnlyDifferences(true)
+	}
+	return k
+}
+
+// StartTableDiff compares two tables by the key column and runs the diff screen.
+func StartTableDiff(left, right TableDataHandler, key string, opts DiffOptions) error {
+	diff, err := DiffTables(left, right, key)
+	if err != nil {
+		return err
+	}
+	k := NewTableDiffRenderer(diff, opts)
+
+	p := tea.NewProgram(k, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		logz.Error("Error running table diff screen: "+err.Error(), map[string]interface{}{
+			"context": "StartTableDiff",
+			"title":   opts.Title,
+			"key":     key,
+		})
+		return err
+	}
+	return nil
+}
+
+// ShowOnlyDifferences hides the rows equal in both tables of a diff, or shows them again. It sets
+// the filter bar, so the filter can be refined further. Tables that are not a diff are left as is.
+func (k *TableRenderer) ShowOnlyDifferences(only bool) {
+	if k.diff == nil {
+		return
+	}
+	if only {
+		k.filter = k.diff.onlyDifferencesFilter()
+	} else {
+		k.filter = ""
+	}
+	k.ApplyFilter()
+}
+
+// toggleOnlyDifferences switches between all the rows of a diff and only those that differ.
+func (k *TableRenderer) toggleOnlyDifferences() {
+	k.ShowOnlyDifferences(k.diff == nil || k.filter != k.diff.onlyDifferencesFilter())
+}
+
+// diffStatus describes the diff for the footer, or "" when the table is not a diff.
+func (k *TableRenderer) diffStatus() string {
+	if k.diff == nil {
+		return ""
+	}
+	counts := k.diff.Counts()
+	return fmt.Sprintf("  +%d -%d ~%d =%d (ctrl+v: only differences)",
+		counts[DiffAdded], counts[DiffRemoved], counts[DiffChanged], counts[DiffSame])
+}
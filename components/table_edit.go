@@ -0,0 +1,421 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/faelmori/logz"
+	. "github.com/faelmori/xtui/types"
+)
+
+// editedCellStyle marks the cells changed since the last commit.
+var editedCellStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FDFF90")).Italic(true)
+
+// ColumnEditor configures how the cells of a column are edited. Type and Rules are the field types
+// and validation rules of the forms; without a Type, values are checked against the column type.
+type ColumnEditor struct {
+	Type        FieldType
+	Rules       []ValidationRule
+	Validate    FormInputValidationString
+	Placeholder string
+	CharLimit   int
+	ReadOnly    bool
+}
+
+// CellChange is a cell edited since the last commit. Row is the index of the row in the rows of the
+// table handler.
+type CellChange struct {
+	Row    int    `json:"row" yaml:"row"`
+	Column string `json:"column" yaml:"column"`
+	Old    string `json:"old" yaml:"old"`
+	New    string `json:"new" yaml:"new"`
+}
+
+// cellEdit is one entry of the undo history. index is the index of the row in the handler rows.
+type cellEdit struct {
+	index    int
+	col      int
+	old, new string
+}
+
+// cellKey identifies a cell by the index of its row in the handler rows and its column, which do
+// not change when rows are sorted or filtered.
+type cellKey struct {
+	row, col int
+}
+
+// cellEditor is the state of the cell editor overlay.
+type cellEditor struct {
+	row    []string
+	index  int
+	col    int
+	input  textinput.Model
+	editor ColumnEditor
+	err    error
+}
+
+// CanEdit reports whether cells of the column can be edited.
+func (k *TableRenderer) CanEdit(header string) bool {
+	if k.provider != nil || !slices.Contains(k.headers, header) {
+		return false
+	}
+	editor, ok := k.editors[header]
+	if ok {
+		return !editor.ReadOnly
+	}
+	return k.editable
+}
+
+// EditCell sets the cell of the filtered row at index i, after validating the value with the column
+// editor. The change is added to the undo history.
+func (k *TableRenderer) EditCell(i int, header, value string) error {
+	if !k.CanEdit(header) {
+		return fmt.Errorf("column %q is not editable", header)
+	}
+	row := k.rowAt(i)
+	index := k.sourceIndex(row)
+	if index < 0 {
+		return fmt.Errorf("row %d not found", i)
+	}
+	if err := k.validateCell(header, value); err != nil {
+		return err
+	}
+	col := slices.Index(k.headers, header)
+	k.applyEdit(cellEdit{index: index, col: col, old: cellAt(row, col), new: value})
+	return nil
+}
+
+func (k *TableRenderer) applyEdit(edit cellEdit) {
+	if edit.old == edit.new || edit.index < 0 || edit.col >= len(k.source[edit.index]) {
+		return
+	}
+	k.edits = append(k.edits, edit)
+	k.redo = nil
+	k.editsChanged()
+}
+
+// Undo reverts the last edit. It reports whether there was one.
+func (k *TableRenderer) Undo() bool {
+	if len(k.edits) == 0 {
+		return false
+	}
+	edit := k.edits[len(k.edits)-1]
+	k.edits = k.edits[:len(k.edits)-1]
+	k.redo = append(k.redo, edit)
+	k.editsChanged()
+	return true
+}
+
+// Redo applies the last undone edit again. It reports whether there was one.
+func (k *TableRenderer) Redo() bool {
+	if len(k.redo) == 0 {
+		return false
+	}
+	edit := k.redo[len(k.redo)-1]
+	k.redo = k.redo[:len(k.redo)-1]
+	k.edits = append(k.edits, edit)
+	k.editsChanged()
+	return true
+}
+
+// Changes returns the net changes since the last commit, one per cell, in the order the cells were
+// first edited. Cells edited back to their original value are left out.
+func (k *TableRenderer) Changes() []CellChange {
+	net := k.netChanges()
+	changes := make([]CellChange, len(net))
+	for i, change := range net {
+		changes[i] = change.CellChange
+	}
+	return changes
+}
+
+// netChange is a CellChange together with the cell it applies to.
+type netChange struct {
+	CellChange
+	key cellKey
+}
+
+func (k *TableRenderer) netChanges() []netChange {
+	var changes []netChange
+	for _, edit := range k.edits {
+		key := cellKey{row: edit.index, col: edit.col}
+		if i := slices.IndexFunc(changes, func(c netChange) bool { return c.key == key }); i >= 0 {
+			changes[i].New = edit.new
+			continue
+		}
+		changes = append(changes, netChange{
+			CellChange: CellChange{Row: edit.index, Column: k.headers[edit.col], Old: edit.old, New: edit.new},
+			key:        key,
+		})
+	}
+	return slices.DeleteFunc(changes, func(c netChange) bool { return c.Old == c.New })
+}
+
+// CommitChanges passes the changes to the commit callback. When it succeeds, the edited values are
+// written into the rows of the table handler, which are left untouched until then, and the undo
+// history is cleared.
+func (k *TableRenderer) CommitChanges() error {
+	changes := k.netChanges()
+	if len(changes) == 0 {
+		return nil
+	}
+	if k.onCommit != nil {
+		if err := k.onCommit(k.Changes()); err != nil {
+			return err
+		}
+	}
+	marks := make(map[int]bool)
+	for _, change := range changes {
+		if _, ok := marks[change.key.row]; !ok {
+			marks[change.key.row] = k.marked[rowKey(k.source[change.key.row])]
+			delete(k.marked, rowKey(k.source[change.key.row]))
+		}
+		k.source[change.key.row][change.key.col] = change.New
+	}
+	// marks follow the committed content of their rows
+	for index, marked := range marks {
+		if marked {
+			k.marked[rowKey(k.source[index])] = true
+		}
+	}
+	k.discardHistory()
+	return nil
+}
+
+// discardHistory forgets the undo history, dropping the uncommitted edits.
+func (k *TableRenderer) discardHistory() {
+	k.edits, k.redo = nil, nil
+	k.editsChanged()
+}
+
+// editsChanged rebuilds the overlay of pending edits from the undo history, updates the working
+// copies of the edited rows and redraws the rows.
+func (k *TableRenderer) editsChanged() {
+	rows := make(map[int]bool)
+	for key := range k.pending {
+		rows[key.row] = true
+	}
+	clear(k.pending)
+	for _, change := range k.netChanges() {
+		k.pending[change.key] = change.New
+		rows[change.key.row] = true
+	}
+	for index := range rows {
+		k.overlayRow(index)
+	}
+	if k.groupBy != "" || len(k.aggregations) > 0 {
+		k.summarize()
+	}
+	k.invalidate()
+}
+
+// overlayRow sets the working copy of the handler row at index to the handler values, with the
+// pending edits on top.
+func (k *TableRenderer) overlayRow(index int) {
+	row, source := k.workingRow(index), k.source[index]
+	for col := range row {
+		if value, ok := k.pending[cellKey{row: index, col: col}]; ok {
+			row[col] = value
+		} else if col < len(source) {
+			row[col] = source[col]
+		}
+	}
+}
+
+// workingRow returns the row shown for the handler row at index. Rows are copied on their first
+// edit, so pending edits never reach the rows of the handler before they are committed.
+func (k *TableRenderer) workingRow(index int) []string {
+	row := k.rows[index]
+	if len(row) == 0 {
+		return row
+	}
+	if _, ok := k.workingRows[&row[0]]; ok {
+		return row
+	}
+	working := slices.Clone(row)
+	k.rows[index] = working
+	if i := slices.IndexFunc(k.filteredRows, func(r []string) bool { return len(r) > 0 && &r[0] == &row[0] }); i >= 0 {
+		k.filteredRows[i] = working
+	}
+	k.workingRows[&working[0]] = index
+	return working
+}
+
+// setRows replaces the rows of the table, dropping the uncommitted edits. The handler rows are kept
+// as the source of the working copies, and the table gets its own list of them, which it may change.
+func (k *TableRenderer) setRows(rows [][]string) {
+	k.source = rows
+	k.rows = slices.Clone(rows)
+	k.edits, k.redo = nil, nil
+	clear(k.pending)
+	clear(k.workingRows)
+}
+
+// isEdited reports whether the cell at col of row changed since the last commit.
+func (k *TableRenderer) isEdited(row []string, col int) bool {
+	if len(row) == 0 {
+		return false
+	}
+	index, ok := k.workingRows[&row[0]]
+	if !ok {
+		return false
+	}
+	_, edited := k.pending[cellKey{row: index, col: col}]
+	return edited
+}
+
+// sourceIndex returns the index of row in the handler rows, or -1.
+func (k *TableRenderer) sourceIndex(row []string) int {
+	if len(row) == 0 {
+		return -1
+	}
+	return slices.IndexFunc(k.rows, func(r []string) bool { return len(r) > 0 && &r[0] == &row[0] })
+}
+
+// validateCell checks a value with the validation rules, the field type and the custom validator
+// of the column editor. Columns without a field type are checked against their column type.
+func (k *TableRenderer) validateCell(header, value string) error {
+	editor := k.editors[header]
+	for _, rule := range editor.Rules {
+		if err := rule.Validate(value, nil); err != nil {
+			return err
+		}
+	}
+	if value != "" {
+		if err := checkCellType(editor.Type, k.ColumnType(header), value); err != nil {
+			return fmt.Errorf("%s: %w", header, err)
+		}
+	}
+	if editor.Validate != nil {
+		return editor.Validate(value)
+	}
+	return nil
+}
+
+func checkCellType(field FieldType, column ColumnType, value string) error {
+	value = strings.TrimSpace(value)
+	switch {
+	case field == FieldInt || (field == "" && column == ColumnInt):
+		if _, err := strconv.Atoi(value); err != nil {
+			return errors.New("must be an integer")
+		}
+	case field == FieldBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return errors.New("must be true or false")
+		}
+	case field == FieldDate || field == FieldTime || (field == "" && column == ColumnTime):
+		if _, err := ParseTime(value); err != nil {
+			return errors.New("must be a date or time")
+		}
+	case field == "" && column == ColumnFloat:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.New("must be a number")
+		}
+	case field == "" && column == ColumnBytes:
+		if _, err := ParseByteSize(value); err != nil {
+			return errors.New("must be a size such as 12K or 1.5 GB")
+		}
+	}
+	return nil
+}
+
+// OpenCellEditor opens the editor on the cell of the selected row under the column cursor.
+func (k *TableRenderer) OpenCellEditor() tea.Cmd {
+	if k.sortCursor >= len(k.headers) {
+		return nil
+	}
+	header := k.headers[k.sortCursor]
+	if !k.CanEdit(header) {
+		return k.notify(Warning, fmt.Sprintf("Column %s is not editable", header))
+	}
+	row := k.rowAt(k.selectedRow)
+	if row == nil {
+		return k.notify(Warning, "Select a row with up/down to edit it")
+	}
+	if k.isGroupHeader(row) {
+		return k.notify(Warning, "Group headers cannot be edited")
+	}
+
+	editor := k.editors[header]
+	input := textinput.New()
+	input.Prompt = ""
+	input.Cursor.Style = focusedStyle
+	input.Placeholder = editor.Placeholder
+	input.CharLimit = editor.CharLimit
+	if editor.Type == FieldPass {
+		input.EchoMode = textinput.EchoPassword
+		input.EchoCharacter = '•'
+	}
+	input.SetValue(cellAt(row, k.sortCursor))
+	input.CursorEnd()
+	cmd := input.Focus()
+	k.cellEditor = &cellEditor{row: row, index: k.sourceIndex(row), col: k.sortCursor, input: input, editor: editor}
+	return cmd
+}
+
+// updateCellEditor handles a key while the cell editor is open.
+func (k *TableRenderer) updateCellEditor(msg tea.KeyMsg) tea.Cmd {
+	e := k.cellEditor
+	switch msg.String() {
+	case "esc":
+		k.cellEditor = nil
+		return nil
+	case "enter":
+		value := e.input.Value()
+		header := k.headers[e.col]
+		if err := k.validateCell(header, value); err != nil {
+			e.err = err
+			return nil
+		}
+		k.cellEditor = nil
+		k.applyEdit(cellEdit{index: e.index, col: e.col, old: cellAt(e.row, e.col), new: value})
+		return nil
+	}
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	e.err = nil
+	return cmd
+}
+
+// commitEdits runs the commit callback and reports the outcome.
+func (k *TableRenderer) commitEdits() tea.Cmd {
+	count := len(k.Changes())
+	if count == 0 {
+		return k.notify(Info, "No changes to commit")
+	}
+	if err := k.CommitChanges(); err != nil {
+		logz.Error("Error committing table changes: "+err.Error(), map[string]interface{}{
+			"context": "CommitChanges",
+			"changes": count,
+		})
+		return k.notify(Error, "Commit failed: "+err.Error())
+	}
+	return k.notify(Info, fmt.Sprintf("Committed %d changes", count))
+}
+
+// editStatus describes the pending changes for the footer, or "" when there are none.
+func (k *TableRenderer) editStatus() string {
+	if len(k.pending) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  Changed: %d (ctrl+w commit)", len(k.pending))
+}
+
+// cellEditorView renders the cell editor overlay.
+func (k *TableRenderer) cellEditorView() string {
+	e := k.cellEditor
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Edit %s (row %d)", k.headers[e.col], k.selectedRow+1)) + "\n\n")
+	b.WriteString(e.input.View() + "\n")
+	if e.err != nil {
+		b.WriteString("\n" + errorStyle.Render(e.err.Error()) + "\n")
+	}
+	b.WriteString("\n" + helpStyle.Render("enter: save • esc: cancel"))
+	return k.overlay(lipgloss.NewStyle().Width(max(40, lipgloss.Width(e.input.Value())+2)).Render(b.String()))
+}
@@ -0,0 +1,360 @@
+package components
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/faelmori/logz"
+	"github.com/faelmori/xtui/components/export"
+	. "github.com/faelmori/xtui/types"
+)
+
+// ExportScope selects which rows a table export contains.
+type ExportScope int
+
+const (
+	// ExportFiltered exports the rows that pass the filter, in their current order.
+	ExportFiltered ExportScope = iota
+	// ExportAll exports every row of the source data, ignoring the filter.
+	ExportAll
+	// ExportSelected exports the marked rows, or the row under the cursor when none is marked.
+	ExportSelected
+)
+
+func (s ExportScope) String() string {
+	switch s {
+	case ExportAll:
+		return "All rows"
+	case ExportSelected:
+		return "Selected rows"
+	default:
+		return "Filtered rows"
+	}
+}
+
+// ExportOptions controls the content of a table export.
+type ExportOptions struct {
+	Scope              ExportScope
+	VisibleColumnsOnly bool
+}
+
+const (
+	// noticeDuration is how long a notification stays below the table.
+	noticeDuration = 4 * time.Second
+	// maxCompletions is how many path completions the export dialog lists.
+	maxCompletions = 6
+	// defaultExportName is the file name proposed by the export dialog, without extension.
+	defaultExportName = "exported_data"
+)
+
+// Fields of the export dialog, in focus order.
+const (
+	exportFieldFormat = iota
+	exportFieldScope
+	exportFieldColumns
+	exportFieldPath
+	exportFieldCount
+)
+
+// exportDialog is the state of the modal opened by the export keys.
+type exportDialog struct {
+	formats     []string
+	format      int
+	scope       ExportScope
+	visibleOnly bool
+	path        textinput.Model
+	focus       int
+	completions []string
+	confirm     bool
+	// columns tells whether the columns field is offered
+	columns bool
+}
+
+// tableNoticeMsg hides the notification with the given id, unless a newer one replaced it.
+type tableNoticeMsg struct{ id int }
+
+// OpenExportDialog shows the export dialog with the given format preselected. An empty or unknown
+// format selects the first registered one.
+func (k *TableRenderer) OpenExportDialog(format string) {
+	k.exportDialog = newExportDialog(format, true)
+}
+
+// newExportDialog creates an export dialog with the given format preselected, or returns nil when
+// no format is registered. columns offers the choice between all and visible columns.
+func newExportDialog(format string, columns bool) *exportDialog {
+	formats := export.Formats()
+	if len(formats) == 0 {
+		return nil
+	}
+	d := &exportDialog{formats: formats, visibleOnly: columns, columns: columns, focus: exportFieldPath}
+	d.format = max(0, slices.Index(formats, format))
+	d.path = textinput.New()
+	d.path.Prompt = ""
+	d.path.Cursor.Style = focusedStyle
+	d.path.SetValue(defaultExportName + "." + d.extension())
+	d.path.Focus()
+	return d
+}
+
+// extension returns the file extension of the selected format.
+func (d *exportDialog) extension() string {
+	if f, ok := export.Get(d.formats[d.format]); ok && f.Extension != "" {
+		return f.Extension
+	}
+	return d.formats[d.format]
+}
+
+// selectFormat moves the format selection by delta, renaming the path when it ends with the
+// extension of the previous format.
+func (d *exportDialog) selectFormat(delta int) {
+	old := "." + d.extension()
+	d.format = (d.format + delta + len(d.formats)) % len(d.formats)
+	if path := d.path.Value(); strings.HasSuffix(path, old) {
+		d.path.SetValue(strings.TrimSuffix(path, old) + "." + d.extension())
+		d.path.CursorEnd()
+	}
+}
+
+// moveFocus moves the focus by delta fields, skipping the columns field when it is not offered.
+func (d *exportDialog) moveFocus(delta int) {
+	d.focus = (d.focus + delta + exportFieldCount) % exportFieldCount
+	if d.focus == exportFieldColumns && !d.columns {
+		d.focus = (d.focus + delta + exportFieldCount) % exportFieldCount
+	}
+	d.completions = nil
+	if d.focus == exportFieldPath {
+		d.path.Focus()
+	} else {
+		d.path.Blur()
+	}
+}
+
+// updateExportDialog handles a key while the export dialog is open.
+func (k *TableRenderer) updateExportDialog(msg tea.KeyMsg) tea.Cmd {
+	submit, cancel, cmd := k.exportDialog.update(msg)
+	switch {
+	case submit:
+		return k.runExportDialog()
+	case cancel:
+		k.exportDialog = nil
+	}
+	return cmd
+}
+
+// update handles a key while the dialog is open. It reports whether the export was confirmed or
+// the dialog cancelled.
+func (d *exportDialog) update(msg tea.KeyMsg) (submit, cancel bool, cmd tea.Cmd) {
+	if d.confirm {
+		d.confirm = false
+		return msg.String() == "y" || msg.String() == "Y", false, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		return false, true, nil
+	case "enter":
+		if _, err := os.Stat(expandHome(d.path.Value())); err == nil {
+			d.confirm = true
+			return false, false, nil
+		}
+		return true, false, nil
+	case "up", "shift+tab":
+		d.moveFocus(-1)
+		return false, false, nil
+	case "down":
+		d.moveFocus(1)
+		return false, false, nil
+	case "tab":
+		if d.focus != exportFieldPath {
+			d.moveFocus(1)
+			return false, false, nil
+		}
+		path, completions := completePath(d.path.Value())
+		d.path.SetValue(path)
+		d.path.CursorEnd()
+		d.completions = completions
+		return false, false, nil
+	}
+
+	switch d.focus {
+	case exportFieldFormat:
+		switch msg.String() {
+		case "left":
+			d.selectFormat(-1)
+		case "right", " ":
+			d.selectFormat(1)
+		}
+	case exportFieldScope:
+		switch msg.String() {
+		case "left":
+			d.scope = (d.scope + 2) % 3
+		case "right", " ":
+			d.scope = (d.scope + 1) % 3
+		}
+	case exportFieldColumns:
+		switch msg.String() {
+		case "left", "right", " ":
+			d.visibleOnly = !d.visibleOnly
+		}
+	case exportFieldPath:
+		d.path, cmd = d.path.Update(msg)
+		d.completions = nil
+	}
+	return false, false, cmd
+}
+
+// runExportDialog writes the export chosen in the dialog, closes it and reports the outcome.
+func (k *TableRenderer) runExportDialog() tea.Cmd {
+	d := k.exportDialog
+	k.exportDialog = nil
+	return k.runExport(d, func(d *exportDialog) TableDataHandler {
+		return k.ExportRows(ExportOptions{Scope: d.scope, VisibleColumnsOnly: d.visibleOnly})
+	})
+}
+
+// tableNotice is the notification shown below a table or a tree until its tableNoticeMsg arrives.
+type tableNotice struct {
+	notice   *Notification
+	noticeID int
+}
+
+// notify shows a notification and returns the command that hides it again.
+func (n *tableNotice) notify(typ NotificationType, message string) tea.Cmd {
+	n.noticeID++
+	n.notice = &Notification{Message: message, Type: typ}
+	id := n.noticeID
+	return tea.Tick(noticeDuration, func(time.Time) tea.Msg {
+		return tableNoticeMsg{id: id}
+	})
+}
+
+// runExport writes the rows returned by data to the file and format chosen in the dialog and
+// reports the outcome.
+func (n *tableNotice) runExport(d *exportDialog, data func(d *exportDialog) TableDataHandler) tea.Cmd {
+	format := d.formats[d.format]
+	filename := expandHome(d.path.Value())
+	if strings.TrimSpace(filename) == "" {
+		return n.notify(Error, "Export failed: no file name given")
+	}
+	rows := data(d)
+	if err := export.ExportToFile(format, filename, rows); err != nil {
+		logz.Error("Error exporting data: "+err.Error(), map[string]interface{}{
+			"context":  "ExportDialog",
+			"format":   format,
+			"filename": filename,
+		})
+		return n.notify(Error, "Export failed: "+err.Error())
+	}
+	return n.notify(Info, fmt.Sprintf("Exported %d rows to %s", len(rows.GetRows()), filename))
+}
+
+// exportDialogView renders the export dialog box.
+func (k *TableRenderer) exportDialogView() string {
+	return k.overlay(k.exportDialog.view("Export table"))
+}
+
+// view renders the content of the dialog under the given title.
+func (d *exportDialog) view(title string) string {
+	label := func(field int, name string) string {
+		if d.focus == field {
+			return focusedStyle.Render(fmt.Sprintf("> %-9s", name))
+		}
+		return fmt.Sprintf("  %-9s", name)
+	}
+	choice := func(field int, value string) string {
+		if d.focus == field {
+			return focusedStyle.Render("‹ " + value + " ›")
+		}
+		return "  " + value + "  "
+	}
+	columns := "All columns"
+	if d.visibleOnly {
+		columns = "Visible columns only"
+	}
+
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(title) + "\n\n")
+	b.WriteString(fmt.Sprintf("%s %s\n", label(exportFieldFormat, "Format"), choice(exportFieldFormat, d.formats[d.format])))
+	b.WriteString(fmt.Sprintf("%s %s\n", label(exportFieldScope, "Rows"), choice(exportFieldScope, d.scope.String())))
+	if d.columns {
+		b.WriteString(fmt.Sprintf("%s %s\n", label(exportFieldColumns, "Columns"), choice(exportFieldColumns, columns)))
+	}
+	b.WriteString(fmt.Sprintf("%s   %s\n", label(exportFieldPath, "Path"), d.path.View()))
+	for _, completion := range d.completions {
+		b.WriteString("              " + blurredStyle.Render(completion) + "\n")
+	}
+
+	b.WriteString("\n")
+	if d.confirm {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("%s already exists. Overwrite? (y/N)", d.path.Value())))
+	} else {
+		b.WriteString(helpStyle.Render("up/down: field • left/right: change • tab: complete path • enter: export • esc: cancel"))
+	}
+	return b.String()
+}
+
+// completePath completes the last element of path with the directories that start with it. It
+// returns the completed path and, when several directories match, their names.
+func completePath(path string) (string, []string) {
+	dir, base := filepath.Split(path)
+	entries, err := os.ReadDir(expandHome(orDot(dir)))
+	if err != nil {
+		return path, nil
+	}
+	var matches []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".")) {
+			continue
+		}
+		if entry.IsDir() {
+			matches = append(matches, name+string(filepath.Separator))
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return path, nil
+	case 1:
+		return dir + matches[0], nil
+	}
+	prefix := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	for !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+	if len(matches) > maxCompletions {
+		matches = append(matches[:maxCompletions], fmt.Sprintf("… %d more", len(matches)-maxCompletions))
+	}
+	return dir + prefix, matches
+}
+
+// expandHome replaces a leading ~ with the home directory of the user.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return home + path[1:]
+}
+
+func orDot(dir string) string {
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
@@ -0,0 +1,281 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	. "github.com/faelmori/xtui/types"
+)
+
+// Filter operators understood by the table filter bar.
+const (
+	FilterContains    = ""
+	FilterEqual       = "="
+	FilterNotEqual    = "!="
+	FilterMatch       = "~"
+	FilterNotMatch    = "!~"
+	FilterGreater     = ">"
+	FilterGreaterOrEq = ">="
+	FilterLess        = "<"
+	FilterLessOrEq    = "<="
+)
+
+// filterOperators is ordered so that two character operators are tried before their prefixes.
+var filterOperators = []string{
+	FilterNotEqual, FilterNotMatch, FilterGreaterOrEq, FilterLessOrEq,
+	FilterEqual, FilterMatch, FilterGreater, FilterLess,
+}
+
+// FilterSyntaxError reports an invalid filter expression and the position where it was found.
+type FilterSyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("filter error at %d: %s", e.Pos+1, e.Msg)
+}
+
+// FilterTerm is a single condition of a filter expression. An empty Column matches any cell.
+type FilterTerm struct {
+	Column string
+	Op     string
+	Value  string
+	Negate bool
+
+	col int
+	re  *regexp.Regexp
+}
+
+// FilterExpr is a parsed filter. A row matches when it satisfies every term.
+type FilterExpr struct {
+	Terms []FilterTerm
+}
+
+// ParseFilter parses a filter expression such as `Status=installed Name~^lib Version>=2.0`.
+//
+// Terms are separated by spaces and combined with AND. Each term is either a bare word, matched as
+// a case-insensitive substring of any cell, or `column<op>value` with one of the operators =, !=,
+// ~ (regexp), !~, >, >=, < and <=. A leading ! negates a term. Column names are matched without
+// case and, like values, may be double quoted to include spaces or operator characters.
+func ParseFilter(expr string, headers []string) (*FilterExpr, error) {
+	f := &FilterExpr{}
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	for _, tok := range tokens {
+		term, termErr := parseFilterTerm(tok.text, tok.pos, headers)
+		if termErr != nil {
+			return nil, termErr
+		}
+		f.Terms = append(f.Terms, term)
+	}
+	return f, nil
+}
+
+type filterToken struct {
+	text string
+	pos  int
+}
+
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	start, quoteStart := -1, -1
+	for i, r := range expr {
+		switch {
+		case r == '"':
+			if start < 0 {
+				start = i
+			}
+			if quoteStart < 0 {
+				quoteStart = i
+			} else {
+				quoteStart = -1
+			}
+		case r == ' ' || r == '\t':
+			if quoteStart < 0 && start >= 0 {
+				tokens = append(tokens, filterToken{text: expr[start:i], pos: start})
+				start = -1
+			}
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	if quoteStart >= 0 {
+		return nil, &FilterSyntaxError{Pos: quoteStart, Msg: "unterminated quote"}
+	}
+	if start >= 0 {
+		tokens = append(tokens, filterToken{text: expr[start:], pos: start})
+	}
+	return tokens, nil
+}
+
+func parseFilterTerm(text string, pos int, headers []string) (FilterTerm, error) {
+	term := FilterTerm{col: -1}
+	if strings.HasPrefix(text, "!") && !strings.HasPrefix(text, FilterNotEqual) && !strings.HasPrefix(text, FilterNotMatch) {
+		term.Negate = true
+		text = text[1:]
+		pos++
+	}
+
+	column, rest, quoted := text, "", false
+	if strings.HasPrefix(text, `"`) {
+		end := strings.Index(text[1:], `"`)
+		column, rest, quoted = text[1:end+1], text[end+2:], true
+	} else if i := strings.IndexAny(text, "=!~<>"); i >= 0 {
+		column, rest = text[:i], text[i:]
+	}
+
+	for _, op := range filterOperators {
+		if strings.HasPrefix(rest, op) {
+			term.Op = op
+			term.Value = unquoteFilterValue(rest[len(op):])
+			break
+		}
+	}
+	if term.Op == FilterContains {
+		if rest != "" {
+			return term, &FilterSyntaxError{Pos: pos + len(text) - len(rest), Msg: fmt.Sprintf("unexpected %q", rest)}
+		}
+		if !quoted {
+			column = unquoteFilterValue(column)
+		}
+		term.Value = column
+		if term.Value == "" {
+			return term, &FilterSyntaxError{Pos: pos, Msg: "empty term"}
+		}
+		return term, nil
+	}
+
+	term.Column = column
+	if column != "" {
+		for i, header := range headers {
+			if strings.EqualFold(header, column) {
+				term.Column, term.col = header, i
+				break
+			}
+		}
+		if term.col < 0 {
+			return term, &FilterSyntaxError{Pos: pos, Msg: fmt.Sprintf("unknown column %q", column)}
+		}
+	}
+
+	if term.Op == FilterMatch || term.Op == FilterNotMatch {
+		re, reErr := regexp.Compile("(?i)" + term.Value)
+		if reErr != nil {
+			return term, &FilterSyntaxError{Pos: pos + len(text) - len(rest), Msg: reErr.Error()}
+		}
+		term.re = re
+	}
+	return term, nil
+}
+
+func unquoteFilterValue(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// Match reports whether the row satisfies all terms. columnType gives the type hint of a column
+// index, used by the ordering operators.
+func (f *FilterExpr) Match(row []string, columnType func(col int) ColumnType) bool {
+	for _, term := range f.Terms {
+		if term.match(row, columnType) == term.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (t FilterTerm) match(row []string, columnType func(col int) ColumnType) bool {
+	if t.col >= 0 {
+		return t.matchCell(cellAt(row, t.col), columnType(t.col))
+	}
+	for i, cell := range row {
+		if t.matchCell(cell, columnType(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t FilterTerm) matchCell(cell string, typ ColumnType) bool {
+	switch t.Op {
+	case FilterContains:
+		return strings.Contains(strings.ToLower(cell), strings.ToLower(t.Value))
+	case FilterEqual:
+		return compareFilterValue(typ, cell, t.Value) == 0
+	case FilterNotEqual:
+		return compareFilterValue(typ, cell, t.Value) != 0
+	case FilterMatch:
+		return t.re.MatchString(cell)
+	case FilterNotMatch:
+		return !t.re.MatchString(cell)
+	case FilterGreater:
+		return compareFilterValue(typ, cell, t.Value) > 0
+	case FilterGreaterOrEq:
+		return compareFilterValue(typ, cell, t.Value) >= 0
+	case FilterLess:
+		return compareFilterValue(typ, cell, t.Value) < 0
+	case FilterLessOrEq:
+		return compareFilterValue(typ, cell, t.Value) <= 0
+	}
+	return false
+}
+
+// compareFilterValue compares like the column sort does, but columns without a type hint are
+// compared as versions, so `Version>=2.0` or `Count>10` also work on untyped columns.
+func compareFilterValue(typ ColumnType, cell, value string) int {
+	if typ == ColumnString {
+		cell, value = strings.ToLower(cell), strings.ToLower(value)
+		if c := CompareVersions(cell, value); c != 0 || cell == value {
+			return c
+		}
+		return strings.Compare(cell, value)
+	}
+	return CompareCells(typ, cell, value)
+}
+
+// ApplyFilter parses the filter bar and keeps only the rows that match it. On a syntax error the
+// previous result is kept and the error is shown below the filter bar.
+func (k *TableRenderer) ApplyFilter() {
+	expr, err := ParseFilter(k.filter, k.headers)
+	k.filterErr = err
+	if err != nil {
+		return
+	}
+	k.filterExpr = expr
+	k.page = 0
+	k.loadFilteredRows()
+	k.SortRows()
+}
+
+func (k *TableRenderer) filterRows(rows [][]string) [][]string {
+	if k.filterExpr == nil || len(k.filterExpr.Terms) == 0 {
+		return append([][]string(nil), rows...)
+	}
+	filtered := make([][]string, 0)
+	for _, row := range rows {
+		if k.filterExpr.Match(row, k.columnTypeAt) {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+func (k *TableRenderer) columnTypeAt(col int) ColumnType {
+	if col < 0 || col >= len(k.headers) {
+		return ColumnString
+	}
+	return k.ColumnType(k.headers[col])
+}
+
+// checkFilter validates the filter bar while typing, so syntax errors show up before enter.
+func (k *TableRenderer) checkFilter() {
+	_, k.filterErr = ParseFilter(k.filter, k.headers)
+}
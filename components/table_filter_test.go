@@ -0,0 +1,129 @@
+package components
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	. "github.com/faelmori/xtui/types"
+)
+
+var filterTestHeaders = []string{"Name", "Version", "Size", "Install Date"}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []FilterTerm
+	}{
+		{expr: "", want: nil},
+		{expr: "lib", want: []FilterTerm{{Value: "lib"}}},
+		{expr: "!lib  ssl", want: []FilterTerm{{Value: "lib", Negate: true}, {Value: "ssl"}}},
+		{expr: `"two words"`, want: []FilterTerm{{Value: "two words"}}},
+		{expr: "name=bash", want: []FilterTerm{{Column: "Name", Op: FilterEqual, Value: "bash"}}},
+		{expr: "Name!=bash", want: []FilterTerm{{Column: "Name", Op: FilterNotEqual, Value: "bash"}}},
+		{expr: "!Name~^lib", want: []FilterTerm{{Column: "Name", Op: FilterMatch, Value: "^lib", Negate: true}}},
+		{expr: "Name!~^lib", want: []FilterTerm{{Column: "Name", Op: FilterNotMatch, Value: "^lib"}}},
+		{
+			expr: "Version>=2.0 Size<1MB",
+			want: []FilterTerm{
+				{Column: "Version", Op: FilterGreaterOrEq, Value: "2.0"},
+				{Column: "Size", Op: FilterLess, Value: "1MB"},
+			},
+		},
+		{expr: "Version>2 Version<=3", want: []FilterTerm{
+			{Column: "Version", Op: FilterGreater, Value: "2"},
+			{Column: "Version", Op: FilterLessOrEq, Value: "3"},
+		}},
+		{expr: `"Install Date">=2024-01-01`, want: []FilterTerm{{Column: "Install Date", Op: FilterGreaterOrEq, Value: "2024-01-01"}}},
+		{expr: `Name="a b"`, want: []FilterTerm{{Column: "Name", Op: FilterEqual, Value: "a b"}}},
+		{expr: "=bash", want: []FilterTerm{{Op: FilterEqual, Value: "bash"}}},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr, filterTestHeaders)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", tt.expr, err)
+			continue
+		}
+		var got []FilterTerm
+		for _, term := range f.Terms {
+			got = append(got, FilterTerm{Column: term.Column, Op: term.Op, Value: term.Value, Negate: term.Negate})
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFilter(%q) = %+v, want %+v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+	}{
+		{expr: `Name="bash`, pos: 5},
+		{expr: `ok Owner=root`, pos: 3},
+		{expr: `Name~[a-`, pos: 4},
+		{expr: `!`, pos: 1},
+	}
+	for _, tt := range tests {
+		_, err := ParseFilter(tt.expr, filterTestHeaders)
+		var syntaxErr *FilterSyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseFilter(%q) error = %v, want a FilterSyntaxError", tt.expr, err)
+			continue
+		}
+		if syntaxErr.Pos != tt.pos {
+			t.Errorf("ParseFilter(%q) error at %d, want %d: %v", tt.expr, syntaxErr.Pos, tt.pos, err)
+		}
+	}
+}
+
+func TestFilterExprMatch(t *testing.T) {
+	hints := map[int]ColumnType{2: ColumnBytes}
+	columnType := func(col int) ColumnType {
+		if typ, ok := hints[col]; ok {
+			return typ
+		}
+		return ColumnString
+	}
+	rows := map[string][]string{
+		"bash":    {"bash", "5.2.15-2", "1.5MB", "2024-03-01"},
+		"libssl":  {"libssl3", "3.0.11-1", "5MB", "2023-11-20"},
+		"libzstd": {"libzstd1", "1.5.4", "900KB", "2024-01-15"},
+	}
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{expr: "", want: []string{"bash", "libssl", "libzstd"}},
+		{expr: "LIB", want: []string{"libssl", "libzstd"}},
+		{expr: "!lib", want: []string{"bash"}},
+		{expr: "lib ssl", want: []string{"libssl"}},
+		{expr: "Name=BASH", want: []string{"bash"}},
+		{expr: "Name!=bash", want: []string{"libssl", "libzstd"}},
+		{expr: "Name~^lib.*1$", want: []string{"libzstd"}},
+		{expr: "Name!~^lib", want: []string{"bash"}},
+		{expr: "Version>=3.0", want: []string{"bash", "libssl"}},
+		{expr: "Version<3.0", want: []string{"libzstd"}},
+		{expr: "Version>5.2.15", want: []string{"bash"}},
+		{expr: "Size>1MB", want: []string{"bash", "libssl"}},
+		{expr: "Size<=900KB", want: []string{"libzstd"}},
+		{expr: `"Install Date">=2024-01-01`, want: []string{"bash", "libzstd"}},
+		{expr: "=1.5.4", want: []string{"libzstd"}},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.expr, filterTestHeaders)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", tt.expr, err)
+			continue
+		}
+		var got []string
+		for _, name := range []string{"bash", "libssl", "libzstd"} {
+			if f.Match(rows[name], columnType) {
+				got = append(got, name)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFilter(%q).Match = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
@@ -1,98 +1,217 @@
 package components
 
 import (
-	"encoding/csv"
-	"encoding/json"
-	"encoding/xml"
 	"fmt"
 	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
 	"github.com/faelmori/logz"
+	"github.com/faelmori/xtui/components/export"
 	. "github.com/faelmori/xtui/types"
-	"gopkg.in/yaml.v2"
 	"os"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type TableRenderer struct {
-	config       FormConfig
-	kTb          *table.Table
-	headers      []string
-	rows         [][]string
-	filter       string
-	filteredRows [][]string
-	sortColumn   int
-	sortAsc      bool
-	page         int
-	pageSize     int
-	search       string
-	selectedRow  int
-	showHelp     bool
-	visibleCols  map[string]bool
+	config        FormConfig
+	kTb           *table.Table
+	headers       []string
+	rows          [][]string
+	filter        string
+	filterFocused bool
+	filterExpr    *FilterExpr
+	filterErr     error
+	filteredRows  [][]string
+	sortKeys      []SortKey
+	sortCursor    int
+	columnTypes   map[string]ColumnType
+	page          int
+	pageSize      int
+	search        string
+	searching     bool
+	searchMatches []searchMatch
+	searchPos     int
+	searchInput   string
+	searchID      int
+	searchHits    map[int]map[int]bool
+	searchVersion int
+	selectedRow   int
+	showHelp      bool
+	visibleCols   map[string]bool
+	columnOrder   []string
+	pinnedCols    int
+	colOffset     int
+	colsRight     int
+	renderedCols  []int
+	marked        map[string]bool
+	actions       []TableAction
+	editable      bool
+	editors       map[string]ColumnEditor
+	onCommit      func(changes []CellChange) error
+	edits         []cellEdit
+	redo          []cellEdit
+	source        [][]string
+	pending       map[cellKey]string
+	workingRows   map[*string]int
+	aggregations  map[string]Aggregation
+	footer        []string
+	groupBy       string
+	groups        []*rowGroup
+	groupedRows   [][]string
+	groupHeaders  map[*string]*rowGroup
+	expanded      map[string]bool
+	valueColors   map[string]lipgloss.Color
+	styleRules    []StyleRule
+	compiledRules []*styleRule
+	ruleMatches   map[*string][]*styleRule
+	ruleVersion   int
+	diff          *TableDiff
+	id            string
+	currentView   string
+	pageLimit     int
+
+	handler         TableDataHandler
+	provider        TableRowProvider
+	refreshInterval time.Duration
+	stream          <-chan []string
+	streamClosed    bool
+	follow          bool
+
+	dataVersion int
+	window      tableWindow
+	windowRows  [][]string
+	colWidths   []int
+
+	width, height int
+	exportDialog  *exportDialog
+	columnPicker  *columnPicker
+	actionMenu    *actionMenu
+	detailPos     DetailPosition
+	detail        fieldPane
+	detailFocused bool
+	record        *fieldPane
+	cellEditor    *cellEditor
+	viewPicker    *viewPicker
+	tableNotice
 }
 
+// TableOptions holds the presentation settings used when a table is driven by a TableDataHandler.
+type TableOptions struct {
+	Title           string
+	CustomStyles    map[string]lipgloss.Color
+	RefreshInterval time.Duration
+	ColumnTypes     map[string]ColumnType
+	Actions         []TableAction
+	DetailPane      DetailPosition
+	// Editable lets every column be edited with F2; Editors configures, or excludes, single columns.
+	Editable bool
+	Editors  map[string]ColumnEditor
+	// OnCommit receives the edited cells when the changes are committed with ctrl+w.
+	OnCommit func(changes []CellChange) error
+	// Aggregations shows a footer row with the given aggregation of each column.
+	Aggregations map[string]Aggregation
+	// GroupBy groups the rows by the values of the column, under expandable group headers.
+	GroupBy string
+	// StyleRules color the cells of the rows matching their conditions.
+	StyleRules []StyleRule
+	// Follow keeps the last page of a streaming table in view as rows arrive.
+	Follow bool
+	// ID identifies the table for its saved views, such as "pkg-list". Without an ID, views are off.
+	ID string
+	// View names the saved view to open. When empty, the view named "default" is opened, if any.
+	View string
+}
+
+// tableRefreshMsg asks the table to reload headers and rows from its handler. tick tells whether it
+// came from the refresh interval or from a TableDataNotifier, so only that source is re-armed.
+type tableRefreshMsg struct{ tick bool }
+
 func NewTableRenderer(config FormConfig, customStyles map[string]lipgloss.Color) *TableRenderer {
 	headers := make([]string, len(config.Fields))
 	for i, field := range config.Fields {
 		headers[i] = field.Placeholder()
 	}
+	return newTableRenderer(config, headers, make([][]string, 0), customStyles)
+}
+
+// NewTableRendererFromHandler creates a table whose headers and rows come from the given handler.
+// When the handler implements TableDataNotifier, or opts.RefreshInterval is set, the table reloads
+// its data while it is running.
+func NewTableRendererFromHandler(handler TableDataHandler, opts TableOptions) *TableRenderer {
+	k := newTableRenderer(FormConfig{Title: opts.Title}, handler.GetHeaders(), handler.GetRows(), opts.CustomStyles)
+	k.handler = handler
+	k.applyOptions(opts)
+	if k.groupBy != "" || len(k.aggregations) > 0 {
+		k.SortRows()
+	}
+	k.restoreView(opts.View)
+	return k
+}
+
+// applyOptions copies the settings of opts that do not depend on the data source.
+func (k *TableRenderer) applyOptions(opts TableOptions) {
+	k.refreshInterval = opts.RefreshInterval
+	k.actions = append(k.actions, opts.Actions...)
+	k.detailPos = opts.DetailPane
+	k.editable = opts.Editable
+	k.editors = opts.Editors
+	k.onCommit = opts.OnCommit
+	k.follow = opts.Follow
+	k.id = opts.ID
+	for header, typ := range opts.ColumnTypes {
+		k.columnTypes[header] = typ
+	}
+	for header, agg := range opts.Aggregations {
+		k.aggregations[header] = agg
+	}
+	k.groupBy = opts.GroupBy
+	k.applyStyleRules(opts.StyleRules)
+}
+
+func newTableRenderer(config FormConfig, headers []string, rows [][]string, customStyles map[string]lipgloss.Color) *TableRenderer {
+	k := &TableRenderer{
+		config:       config,
+		headers:      headers,
+		sortCursor:   0,
+		columnTypes:  make(map[string]ColumnType),
+		marked:       make(map[string]bool),
+		pending:      make(map[cellKey]string),
+		workingRows:  make(map[*string]int),
+		aggregations: make(map[string]Aggregation),
+		groupHeaders: make(map[*string]*rowGroup),
+		expanded:     make(map[string]bool),
+		ruleMatches:  make(map[*string][]*styleRule),
+		page:         0,
+		search:       "",
+		selectedRow:  -1,
+		showHelp:     false,
+	}
+
+	// typed text goes into the filter until esc leaves it
+	k.filterFocused = true
 
-	rows := make([][]string, 0)
-	re := lipgloss.NewRenderer(os.Stdout)
-	baseStyle := re.NewStyle().Padding(0, 1)
-	headerStyle := baseStyle.Foreground(lipgloss.Color("252")).Bold(true)
-	selectedStyle := baseStyle.Foreground(lipgloss.Color("#01BE85")).Background(lipgloss.Color("#00432F"))
+	// cells whose value names a custom style, such as Info or Error, are drawn in its color
+	k.setRows(rows)
+	k.filteredRows = slices.Clone(k.rows)
 
-	defaultTypeColors := map[string]lipgloss.Color{
+	k.valueColors = map[string]lipgloss.Color{
 		"Info":    lipgloss.Color("#75FBAB"),
 		"Warning": lipgloss.Color("#FDFF90"),
 		"Error":   lipgloss.Color("#FF7698"),
 		"Debug":   lipgloss.Color("#929292"),
 	}
-
 	for key, value := range customStyles {
-		defaultTypeColors[key] = value
+		k.valueColors[key] = value
 	}
 
-	t := table.New().
+	k.kTb = table.New().
 		Headers(headers...).
-		Rows(rows...).
-		Border(lipgloss.NormalBorder()).
-		BorderStyle(re.NewStyle().Foreground(lipgloss.Color("238"))).
-		StyleFunc(func(row, col int) lipgloss.Style {
-			if row == 0 {
-				return headerStyle
-			}
-
-			rowIndex := row - 1
-			if rowIndex < 0 || rowIndex >= len(rows) {
-				return baseStyle
-			}
-
-			if rows[rowIndex][1] == "Bug" {
-				return selectedStyle
-			}
-
-			switch col {
-			case 2, 3:
-				c := defaultTypeColors
-
-				if col >= len(rows[rowIndex]) {
-					return baseStyle
-				}
-
-				color, ok := c[rows[rowIndex][col]]
-				if !ok {
-					return baseStyle
-				}
-				return baseStyle.Foreground(color)
-			}
-			return baseStyle.Foreground(lipgloss.Color("252"))
-		}).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("238"))).
+		StyleFunc(k.cellStyle).
 		Border(lipgloss.ThickBorder())
 
 	pageSizeLimitStr := os.Getenv("KBX_PAGE_SIZE_LIMIT")
@@ -108,31 +227,83 @@ func NewTableRenderer(config FormConfig, customStyles map[string]lipgloss.Color)
 	} else if pageSizeLimit < 1 {
 		pageSizeLimit = 20
 	}
+	k.pageSize = pageSizeLimit
+
+	k.visibleCols = make(map[string]bool)
+	k.syncColumnOrder()
 
-	visibleCols := make(map[string]bool)
-	for _, header := range headers {
-		visibleCols[header] = true
+	k.sampleColumnWidths()
+	k.syncHeaders()
+	k.syncTableRows()
+	return k
+}
+
+func (k *TableRenderer) Init() tea.Cmd {
+	return tea.Batch(k.waitForChange(), k.refreshTick(), k.waitForRows())
+}
+
+// waitForChange returns a command that blocks until a TableDataNotifier handler signals new data.
+func (k *TableRenderer) waitForChange() tea.Cmd {
+	notifier, ok := k.handler.(TableDataNotifier)
+	if !ok {
+		return nil
 	}
+	return func() tea.Msg {
+		if _, open := <-notifier.Changed(); !open {
+			return nil
+		}
+		return tableRefreshMsg{}
+	}
+}
 
-	return &TableRenderer{
-		config:       config,
-		kTb:          t,
-		headers:      headers,
-		rows:         rows,
-		filteredRows: rows,
-		sortColumn:   -1,
-		sortAsc:      true,
-		page:         0,
-		pageSize:     pageSizeLimit,
-		search:       "",
-		selectedRow:  -1,
-		showHelp:     false,
-		visibleCols:  visibleCols,
+// refreshTick returns a command that fires after the refresh interval, or nil when polling is off.
+func (k *TableRenderer) refreshTick() tea.Cmd {
+	if (k.handler == nil && k.provider == nil) || k.refreshInterval <= 0 {
+		return nil
 	}
+	return tea.Tick(k.refreshInterval, func(time.Time) tea.Msg {
+		return tableRefreshMsg{tick: true}
+	})
 }
 
-func (k *TableRenderer) Init() tea.Cmd {
-	return nil
+// Refresh reloads headers and rows from the table handler or provider, keeping the current
+// filter, sort and page as far as the new data allows.
+func (k *TableRenderer) Refresh() {
+	var headers []string
+	switch {
+	case k.handler != nil:
+		headers = k.handler.GetHeaders()
+	case k.provider != nil:
+		headers = k.provider.GetHeaders()
+	default:
+		return
+	}
+	if !slices.Equal(headers, k.headers) {
+		k.headers = headers
+		k.syncColumnOrder()
+		if k.sortCursor >= len(headers) {
+			k.sortCursor = 0
+		}
+		k.sortKeys = slices.DeleteFunc(k.sortKeys, func(key SortKey) bool {
+			return !slices.Contains(headers, key.Column)
+		})
+		k.applyStyleRules(k.styleRules)
+	}
+	if k.handler != nil {
+		// reloaded rows replace the edited ones, so uncommitted edits are dropped
+		k.setRows(k.handler.GetRows())
+		k.discardHistory()
+	}
+	k.sampleColumnWidths()
+	k.loadFilteredRows()
+	k.SortRows()
+	count := k.RowCount()
+	if k.page > 0 && k.page*k.pageSize >= count {
+		k.page = max(0, (count-1)/k.pageSize)
+	}
+	if k.selectedRow >= count {
+		k.selectedRow = count - 1
+	}
 }
 
 func (k *TableRenderer) RowsNavigate(direction string) error {
@@ -145,345 +316,427 @@ func (k *TableRenderer) RowsNavigate(direction string) error {
 	if k.selectedRow < 0 {
 		k.selectedRow = 0
 	}
-	if k.selectedRow >= len(k.filteredRows) {
-		k.selectedRow = len(k.filteredRows) - 1
+	if k.selectedRow >= k.RowCount() {
+		k.selectedRow = k.RowCount() - 1
 	}
-
-	if k.selectedRow >= 0 && len(k.filteredRows) > 0 {
-		k.kTb.StyleFunc(func(row, col int) lipgloss.Style {
-			if row == k.selectedRow {
-				return lipgloss.NewStyle().Foreground(lipgloss.Color("#01BE85")).Background(lipgloss.Color("#00432F"))
-			}
-			return lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
-		})
-	} else if k.selectedRow == 0 {
-		k.kTb.StyleFunc(func(row, col int) lipgloss.Style {
-			if row == 1 {
-				return lipgloss.NewStyle().Foreground(lipgloss.Color("#01BE85")).Background(lipgloss.Color("#00432F"))
-			}
-			return lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
-		})
-	} else {
-		k.kTb.StyleFunc(func(row, col int) lipgloss.Style {
-			return lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
-		})
+	if k.selectedRow >= 0 {
+		k.page = k.selectedRow / k.pageSize
 	}
+
 	return nil
 }
 
 func (k *TableRenderer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch message := msg.(type) {
+	case tableRefreshMsg:
+		k.Refresh()
+		if message.tick {
+			cmd = k.refreshTick()
+		} else {
+			cmd = k.waitForChange()
+		}
+	case tableRowsMsg:
+		k.AppendRows(message.rows)
+		if message.closed {
+			k.stream = nil
+			k.streamClosed = true
+		} else {
+			cmd = k.waitForRows()
+		}
+	case tableActionMsg:
+		cmd = k.actionDone(message)
+	case tableNoticeMsg:
+		if message.id == k.noticeID {
+			k.notice = nil
+		}
+	case tableSearchMsg:
+		if message.id == k.searchID && k.searchInput != k.search {
+			k.Search(k.searchInput)
+		}
 	case tea.WindowSizeMsg:
-		k.kTb = k.kTb.Width(message.Width)
-		k.kTb = k.kTb.Height(message.Height)
+		k.width, k.height = message.Width, message.Height
+		k.applySize()
 	case tea.KeyMsg:
+		if k.exportDialog != nil {
+			return k, k.updateExportDialog(message)
+		}
+		if k.columnPicker != nil {
+			k.updateColumnPicker(message)
+			return k, nil
+		}
+		if k.actionMenu != nil {
+			return k, k.updateActionMenu(message)
+		}
+		if k.viewPicker != nil {
+			return k, k.updateViewPicker(message)
+		}
+		if k.cellEditor != nil {
+			cmd = k.updateCellEditor(message)
+			k.syncTableRows()
+			return k, cmd
+		}
+		if k.record != nil {
+			cmd = k.updateRecordView(message)
+			k.syncTableRows()
+			return k, cmd
+		}
+		if k.detailFocused {
+			return k, k.updateDetailPane(message)
+		}
+		if k.searching {
+			cmd = k.updateSearchInput(message)
+			k.syncTableRows()
+			return k, cmd
+		}
+		if k.filterFocused && k.updateFilterInput(message) {
+			k.syncTableRows()
+			return k, nil
+		}
 		switch message.String() {
-		case "q", "ctrl+c":
+		case "ctrl+c":
 			return k, tea.Quit
+		case "q":
+			// reached once the filter is left, so q can be typed into it
+			return k, tea.Quit
+		case "/":
+			k.searching = true
+			k.Search("")
+		case "n", "N":
+			if k.search == "" {
+				k.filterFocused = true
+				k.typeFilter(message)
+			} else if message.String() == "n" {
+				k.NextMatch(1)
+			} else {
+				k.NextMatch(-1)
+			}
 		case "enter":
+			k.filterFocused = false
 			k.ApplyFilter()
-			if k.selectedRow >= 0 && k.selectedRow < len(k.filteredRows) {
-				row := k.filteredRows[k.selectedRow]
-				_ = clipboard.WriteAll(strings.Join(row, "\t"))
+			if rows := k.SelectedRows(); len(rows) > 0 {
+				lines := make([]string, len(rows))
+				for i, row := range rows {
+					lines[i] = strings.Join(row, "\t")
+				}
+				_ = clipboard.WriteAll(strings.Join(lines, "\n"))
 			}
 		case "backspace":
-			if len(k.filter) > 0 {
-				k.filter = k.filter[:len(k.filter)-1]
+			k.filterFocused = true
+			if runes := []rune(k.filter); len(runes) > 0 {
+				k.filter = string(runes[:len(runes)-1])
+				k.checkFilter()
 			}
 		case "esc":
+			if k.search != "" {
+				k.Search("")
+				break
+			}
 			k.selectedRow = -1
+			k.ClearMarks()
+		case " ":
+			if k.selectedRow < 0 {
+				k.filterFocused = true
+				k.typeFilter(message)
+				break
+			}
+			if k.ToggleGroup(k.selectedRow) {
+				break
+			}
+			k.ToggleMark(k.selectedRow)
+			_ = k.RowsNavigate("down")
+		case "shift+down":
+			k.markRange("down")
+		case "shift+up":
+			k.markRange("up")
+		case "ctrl+a":
+			k.MarkAll()
+		case "ctrl+r":
+			cmd = k.OpenActionMenu()
+		case "ctrl+d":
+			k.SetDetailPane((k.detailPos + 1) % 3)
+		case "ctrl+f":
+			cmd = k.OpenRecordView()
+		case "f2":
+			cmd = k.OpenCellEditor()
+		case "ctrl+z":
+			k.Undo()
+		case "ctrl+n":
+			k.Redo()
+		case "ctrl+w":
+			cmd = k.commitEdits()
+		case "tab":
+			if k.detailPos != DetailHidden {
+				k.detailFocused = true
+			}
 		case "ctrl+o":
-			k.sortColumn = (k.sortColumn + 1) % len(k.headers)
-			k.sortAsc = !k.sortAsc
-			k.SortRows()
+			k.moveSortCursor()
+		case "f3":
+			cmd = k.OpenViewPicker()
+		case "ctrl+v":
+			k.toggleOnlyDifferences()
+		case "ctrl+b":
+			k.cycleAggregation()
+		case "ctrl+u":
+			if k.sortCursor < len(k.headers) && k.groupBy != k.headers[k.sortCursor] {
+				k.SetGroupBy(k.headers[k.sortCursor])
+			} else {
+				k.SetGroupBy("")
+			}
+		case "ctrl+s":
+			if k.sortCursor < len(k.headers) {
+				k.SortBy(k.headers[k.sortCursor])
+			}
+		case "ctrl+t":
+			if k.sortCursor < len(k.headers) {
+				k.ToggleSortDirection(k.headers[k.sortCursor])
+			}
+		case "ctrl+k":
+			if k.sortCursor < len(k.headers) {
+				k.ToggleSortKey(k.headers[k.sortCursor])
+			}
 		case "right":
-			if (k.page+1)*k.pageSize < len(k.filteredRows) {
+			if (k.page+1)*k.pageSize < k.RowCount() {
 				k.page++
 			}
 		case "left":
 			if k.page > 0 {
 				k.page--
 			}
+			k.follow = false
+		case "end":
+			k.Follow(true)
+		case "home":
+			k.follow = false
+			k.page = 0
+		case "shift+right":
+			k.ScrollColumns(1)
+		case "shift+left":
+			k.ScrollColumns(-1)
+		case "ctrl+g":
+			k.OpenColumnPicker()
 		case "down":
 			_ = k.RowsNavigate("down")
 		case "up":
 			_ = k.RowsNavigate("up")
+			k.follow = false
 		case "ctrl+e":
-			k.ExportToCSV("exported_data.csv")
+			k.OpenExportDialog("csv")
 		case "ctrl+h":
 			k.showHelp = !k.showHelp
 		case "ctrl+y":
-			k.ExportToYAML("exported_data.yaml")
+			k.OpenExportDialog("yaml")
 		case "ctrl+j":
-			k.ExportToJSON("exported_data.json")
+			k.OpenExportDialog("json")
 		case "ctrl+x":
-			k.ExportToXML("exported_data.xml")
+			k.OpenExportDialog("xml")
 		case "ctrl+l":
-			k.ExportToExcel("exported_data.xlsx")
+			k.OpenExportDialog("xlsx")
 		case "ctrl+p":
-			k.ExportToPDF("exported_data.pdf")
+			k.OpenExportDialog("pdf")
 		case "ctrl+m":
-			k.ExportToMarkdown("exported_data.md")
-		case "ctrl+c":
-			k.ToggleColumnVisibility()
+			k.OpenExportDialog("markdown")
 		default:
-			k.filter += message.String()
+			if message.Type == tea.KeyRunes && !message.Alt {
+				k.filterFocused = true
+				k.typeFilter(message)
+			}
 		}
 	}
-	k.kTb.ClearRows()                             // Limpa as linhas da tabela antes de adicionar as novas
-	k.kTb = k.kTb.Rows(k.GetCurrentPageRows()...) // Atualiza a tabela com as linhas atuais
+	k.syncTableRows() // Atualiza a tabela com as linhas da página atual, se mudaram
 	return k, cmd
 }
 
-func (k *TableRenderer) ApplyFilter() {
-	if k.filter == "" {
-		k.filteredRows = k.rows
-	} else {
-		var filtered [][]string
-		for _, row := range k.rows {
-			for _, cell := range row {
-				if strings.Contains(strings.ToLower(cell), strings.ToLower(k.filter)) {
-					filtered = append(filtered, row)
-					break
-				}
-			}
-		}
-		k.filteredRows = filtered
+// typeFilter adds the typed characters, or a space, to the filter. Other keys, such as function
+// keys or alt combinations, are ignored instead of being typed by name.
+func (k *TableRenderer) typeFilter(msg tea.KeyMsg) {
+	switch {
+	case msg.Type == tea.KeySpace:
+		k.filter += " "
+	case msg.Type == tea.KeyRunes && !msg.Alt:
+		k.filter += string(msg.Runes)
+	default:
+		return
 	}
-	k.kTb = k.kTb.Rows(k.GetCurrentPageRows()...)
+	k.checkFilter()
 }
 
-func (k *TableRenderer) SortRows() {
-	sort.SliceStable(k.filteredRows, func(i, j int) bool {
-		if k.sortAsc {
-			return k.filteredRows[i][k.sortColumn] < k.filteredRows[j][k.sortColumn]
-		}
-		return k.filteredRows[i][k.sortColumn] > k.filteredRows[j][k.sortColumn]
-	})
-	k.kTb = k.kTb.Rows(k.GetCurrentPageRows()...)
+// updateFilterInput handles a key while the filter is focused and reports whether it was used.
+// Typed text, spaces, q, n and N included, goes into the filter, so terms such as
+// `Status=installed Name~^lib` or `qemu` can be typed while a row is selected. esc leaves the
+// filter, and the keys of the table, such as space to mark a row or q to quit, apply again; typing
+// focuses it back.
+func (k *TableRenderer) updateFilterInput(msg tea.KeyMsg) bool {
+	switch {
+	case msg.Type == tea.KeyEsc:
+		k.filterFocused = false
+	case msg.String() == "/" && k.filter == "":
+		// starts a search
+		return false
+	case msg.Type == tea.KeySpace, msg.Type == tea.KeyRunes && !msg.Alt:
+		k.typeFilter(msg)
+	default:
+		return false
+	}
+	return true
 }
 
+// GetCurrentPageRows returns the filtered rows shown on the current page.
 func (k *TableRenderer) GetCurrentPageRows() [][]string {
-	start := k.page * k.pageSize
-	end := start + k.pageSize
-	if end > len(k.filteredRows) {
-		end = len(k.filteredRows)
-	}
-	return k.filteredRows[start:end]
+	k.syncTableRows()
+	return k.windowRows
 }
 
 func (k *TableRenderer) View() string {
 	helpText := "\nAtalhos:\n" +
-		"  - q, ctrl+c: Sair\n" +
-		"  - enter: Copiar linha selecionada para o clipboard\n" +
-		"  - esc: Sair do modo seleção\n" +
+		"  - q, ctrl+c: Sair (q fora do filtro, depois de esc)\n" +
+		"  - /: Busca aproximada nas células, destacando os caracteres encontrados (enter confirma)\n" +
+		"  - n, N: Ir para o próximo/anterior resultado da busca (esc encerra a busca)\n" +
+		"  - enter: Copiar as linhas marcadas (ou a selecionada) para o clipboard\n" +
+		"  - esc: Sair do filtro; de novo, sair do modo seleção e desmarcar as linhas\n" +
+		"  - space: Marcar/desmarcar a linha selecionada (fora do filtro; digitar volta ao filtro)\n" +
+		"  - shift+up/down: Marcar um intervalo de linhas\n" +
+		"  - ctrl+a: Marcar/desmarcar todas as linhas filtradas\n" +
+		"  - ctrl+r: Executar uma ação nas linhas marcadas\n" +
+		"  - ctrl+d: Mostrar o painel de detalhes à direita, embaixo ou ocultá-lo\n" +
+		"  - tab: Alternar o foco entre a tabela e o painel de detalhes (enter/c copia o campo)\n" +
+		"  - ctrl+f: Abrir a linha selecionada em tela cheia (n/p: próxima/anterior)\n" +
+		"  - f2: Editar a célula da linha selecionada na coluna escolhida com ctrl+o\n" +
+		"  - ctrl+z, ctrl+n: Desfazer e refazer edições\n" +
+		"  - ctrl+w: Confirmar as edições\n" +
 		"  - backspace: Remover último caractere do filtro\n" +
-		"  - ctrl+o: Alternar ordenação\n" +
+		"  - filtro: Status=installed Name~^lib Version>=2.0 !Method=auto (enter aplica)\n" +
+		"  - ctrl+o: Selecionar a próxima coluna para ordenação\n" +
+		"  - ctrl+s: Ordenar pela coluna selecionada (de novo inverte a direção)\n" +
+		"  - ctrl+t: Inverter a direção da coluna selecionada\n" +
+		"  - ctrl+k: Adicionar/remover a coluna selecionada como chave de ordenação\n" +
+		"  - ctrl+b: Alternar o totalizador da coluna selecionada (count, sum, avg, min, max, distinct)\n" +
+		"  - f3: Abrir, salvar ou excluir visões salvas (filtro, ordenação, colunas, agrupamento)\n" +
+		"  - ctrl+v: Mostrar só as diferenças, em uma comparação de tabelas\n" +
+		"  - ctrl+u: Agrupar pela coluna selecionada (de novo desfaz; space expande o grupo)\n" +
 		"  - right: Próxima página\n" +
 		"  - left: Página anterior\n" +
 		"  - down: Selecionar próxima linha\n" +
+		"  - end, home: Seguir as novas linhas (última página) e voltar à primeira página\n" +
 		"  - up: Selecionar linha anterior\n" +
-		"  - ctrl+e: Exportar para CSV\n" +
-		"  - ctrl+y: Exportar para YAML\n" +
-		"  - ctrl+j: Exportar para JSON\n" +
-		"  - ctrl+x: Exportar para XML\n" +
-		"  - ctrl+l: Exportar para Excel\n" +
-		"  - ctrl+p: Exportar para PDF\n" +
-		"  - ctrl+m: Exportar para Markdown\n" +
-		"  - ctrl+c: Alternar visibilidade das colunas\n"
+		"  - ctrl+e: Exportar (abre o diálogo com CSV selecionado)\n" +
+		"  - ctrl+y, ctrl+j, ctrl+x: Exportar com YAML, JSON ou XML selecionado\n" +
+		"  - ctrl+l, ctrl+p, ctrl+m: Exportar com Excel, PDF ou Markdown selecionado\n" +
+		"  - ctrl+g: Escolher, reordenar e fixar colunas\n" +
+		"  - shift+left/right: Rolar as colunas não fixadas\n"
 
 	toggleHelpText := "\nPressione ctrl+h para exibir/ocultar os atalhos."
-
-	if k.showHelp {
-		return fmt.Sprintf("\nFilter: %s\n\n%s\nPage: %d/%d\n%s%s", k.filter, k.kTb.String(), k.page+1, (len(k.filteredRows)+k.pageSize-1)/k.pageSize, helpText, toggleHelpText)
+	if k.notice != nil {
+		toggleHelpText = "\n" + k.notice.Render() + toggleHelpText
 	}
-	return fmt.Sprintf("\nFilter: %s\n\n%s\nPage: %d/%d\n%s", k.filter, k.kTb.String(), k.page+1, (len(k.filteredRows)+k.pageSize-1)/k.pageSize, toggleHelpText)
-}
 
-func (k *TableRenderer) ExportToCSV(filename string) {
-	file, err := os.Create(filename)
-	if err != nil {
-		logz.Error("Error creating file: "+err.Error(), map[string]interface{}{
-			"context":  "ExportToCSV",
-			"filename": filename,
-		})
-		return
+	if k.exportDialog != nil {
+		return k.exportDialogView()
 	}
-	defer func(file *os.File) {
-		_ = file.Close()
-	}(file)
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// Write headers
-	if writerErr := writer.Write(k.headers); writerErr != nil {
-		logz.Error("Error writing headers to CSV.", map[string]interface{}{
-			"context": "ExportToCSV",
-			"headers": k.headers,
-			"error":   writerErr.Error(),
-		})
-		return
+	if k.columnPicker != nil {
+		return k.columnPickerView()
 	}
-
-	// Write rows
-	for _, row := range k.filteredRows {
-		if writerRowsErr := writer.Write(row); writerRowsErr != nil {
-			logz.Error("Error writing row to CSV.", map[string]interface{}{
-				"context": "ExportToCSV",
-				"row":     row,
-				"error":   writerRowsErr.Error(),
-			})
-			return
-		}
+	if k.actionMenu != nil {
+		return k.actionMenuView()
 	}
-
-	logz.Info("Data exported to CSV.", map[string]interface{}{
-		"context":  "ExportToCSV",
-		"filename": filename,
-	})
-}
-
-func (k *TableRenderer) ExportToYAML(filename string) {
-	file, err := os.Create(filename)
-	if err != nil {
-		logz.Error("Error creating file,", map[string]interface{}{
-			"context":  "ExportToYAML",
-			"filename": filename,
-			"error":    err.Error(),
-		})
-		return
+	if k.record != nil {
+		return k.recordView()
 	}
-	defer func(file *os.File) {
-		_ = file.Close()
-	}(file)
-
-	data := make([]map[string]string, len(k.filteredRows))
-	for i, row := range k.filteredRows {
-		rowData := make(map[string]string)
-		for j, cell := range row {
-			rowData[k.headers[j]] = cell
-		}
-		data[i] = rowData
+	if k.cellEditor != nil {
+		return k.cellEditorView()
+	}
+	if k.viewPicker != nil {
+		return k.viewPickerView()
 	}
+	tableView := k.withDetailPane(k.kTb.String())
 
-	encoder := yaml.NewEncoder(file)
-	defer func(encoder *yaml.Encoder) {
-		_ = encoder.Close()
-	}(encoder)
+	filterBar := k.filter
+	if k.filterFocused {
+		filterBar += "▏"
+	}
+	if k.filterErr != nil {
+		filterBar += "\n" + errorStyle.Render(k.filterErr.Error())
+	}
 
-	if err := encoder.Encode(data); err != nil {
-		logz.Error("Error writing data to YAML.", map[string]interface{}{
-			"context": "ExportToYAML",
-			"data":    data,
-			"error":   err.Error(),
-		})
-		return
+	if k.showHelp {
+		return fmt.Sprintf("\nFilter: %s\n\n%s\nPage: %d/%d%s\n%s%s", filterBar, tableView, k.page+1, (k.RowCount()+k.pageSize-1)/k.pageSize, k.columnsStatus()+k.groupStatus()+k.selectionStatus()+k.editStatus()+k.streamStatus()+k.diffStatus()+k.viewStatus()+k.searchStatus(), helpText, toggleHelpText)
 	}
+	return fmt.Sprintf("\nFilter: %s\n\n%s\nPage: %d/%d%s\n%s", filterBar, tableView, k.page+1, (k.RowCount()+k.pageSize-1)/k.pageSize, k.columnsStatus()+k.groupStatus()+k.selectionStatus()+k.editStatus()+k.streamStatus()+k.diffStatus()+k.viewStatus()+k.searchStatus(), toggleHelpText)
+}
 
-	logz.Info("Data exported to YAML.", map[string]interface{}{
-		"context":  "ExportToYAML",
-		"filename": filename,
-	})
+// overlay renders content as a bordered box centered on the screen, replacing the table.
+func (k *TableRenderer) overlay(content string) string {
+	return overlayBox(content, k.width, k.height)
 }
 
-func (k *TableRenderer) ExportToJSON(filename string) {
-	file, err := os.Create(filename)
-	if err != nil {
-		logz.Error("Error creating file.", map[string]interface{}{
-			"context":  "ExportToJSON",
-			"filename": filename,
-			"error":    err.Error(),
-		})
-		return
+// overlayBox renders content as a bordered box centered on a screen of the given size, if known.
+func overlayBox(content string, width, height int) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#01BE85")).
+		Padding(1, 2).
+		Render(content)
+	if width > 0 && height > 0 {
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
 	}
-	defer func(file *os.File) {
-		_ = file.Close()
-	}(file)
+	return box
+}
 
-	data := make([]map[string]string, len(k.filteredRows))
-	for i, row := range k.filteredRows {
-		rowData := make(map[string]string)
-		for j, cell := range row {
-			rowData[k.headers[j]] = cell
-		}
-		data[i] = rowData
-	}
+// ExportData returns the filtered rows, in their current order, restricted to the visible columns.
+func (k *TableRenderer) ExportData() TableDataHandler {
+	return k.ExportRows(ExportOptions{Scope: ExportFiltered, VisibleColumnsOnly: true})
+}
 
-	encoder := json.NewEncoder(file)
-	if err := encoder.Encode(data); err != nil {
-		logz.Error("Error writing data to JSON.", map[string]interface{}{
-			"context": "ExportToJSON",
-			"data":    data,
-			"error":   err.Error(),
-		})
-		return
+// ExportRows returns the rows and columns selected by opts, ready to be written by an exporter.
+func (k *TableRenderer) ExportRows(opts ExportOptions) TableDataHandler {
+	var rows [][]string
+	switch opts.Scope {
+	case ExportAll:
+		rows = k.sourceRows()
+	case ExportSelected:
+		rows = k.SelectedRows()
+	default:
+		rows = k.dataRows()
 	}
+	columns := k.ColumnOrder()
+	if opts.VisibleColumnsOnly {
+		columns = k.VisibleColumns()
+	}
+	table := export.NewTable(k.headers, rows)
+	table.Types = k.columnTypes
+	table.Title = k.config.Title
+	return export.Project(table, columns)
+}
 
-	logz.Info("Data exported to JSON.", map[string]interface{}{
-		"context":  "ExportToJSON",
-		"filename": filename,
-	})
+// Export writes the visible columns of the filtered rows to filename with a registered format.
+// An empty format is guessed from the file extension.
+func (k *TableRenderer) Export(format, filename string) error {
+	return export.ExportToFile(format, filename, k.ExportData())
 }
 
-func (k *TableRenderer) ExportToXML(filename string) {
-	file, err := os.Create(filename)
-	if err != nil {
-		logz.Error("Error creating file.", map[string]interface{}{
-			"context":  "ExportToXML",
+func (k *TableRenderer) exportToFile(format, filename string) {
+	if err := k.Export(format, filename); err != nil {
+		logz.Error("Error exporting data: "+err.Error(), map[string]interface{}{
+			"context":  "Export",
+			"format":   format,
 			"filename": filename,
-			"error":    err.Error(),
 		})
 		return
 	}
-	defer func(file *os.File) {
-		_ = file.Close()
-	}(file)
-
-	data := make([]map[string]string, len(k.filteredRows))
-	for i, row := range k.filteredRows {
-		rowData := make(map[string]string)
-		for j, cell := range row {
-			rowData[k.headers[j]] = cell
-		}
-		data[i] = rowData
-	}
-
-	encoder := xml.NewEncoder(file)
-	if err := encoder.Encode(data); err != nil {
-		logz.Error("Error writing data to XML.", map[string]interface{}{
-			"context": "ExportToXML",
-			"data":    data,
-			"error":   err.Error(),
-		})
-		return
-	}
-
-	logz.Info("Data exported to XML.", map[string]interface{}{
-		"context":  "ExportToXML",
+	logz.Info("Data exported.", map[string]interface{}{
+		"context":  "Export",
+		"format":   format,
 		"filename": filename,
 	})
 }
 
-func (k *TableRenderer) ExportToExcel(filename string) {
-	// Implementation for exporting to Excel
-}
-
-func (k *TableRenderer) ExportToPDF(filename string) {
-	// Implementation for exporting to PDF
-}
-
-func (k *TableRenderer) ExportToMarkdown(filename string) {
-	// Implementation for exporting to Markdown
-}
-
-func (k *TableRenderer) ToggleColumnVisibility() {
-	for header := range k.visibleCols {
-		k.visibleCols[header] = !k.visibleCols[header]
-	}
-	k.kTb = k.kTb.Rows(k.GetCurrentPageRows()...)
-}
+func (k *TableRenderer) ExportToCSV(filename string)      { k.exportToFile("csv", filename) }
+func (k *TableRenderer) ExportToYAML(filename string)     { k.exportToFile("yaml", filename) }
+func (k *TableRenderer) ExportToJSON(filename string)     { k.exportToFile("json", filename) }
+func (k *TableRenderer) ExportToXML(filename string)      { k.exportToFile("xml", filename) }
+func (k *TableRenderer) ExportToExcel(filename string)    { k.exportToFile("xlsx", filename) }
+func (k *TableRenderer) ExportToPDF(filename string)      { k.exportToFile("pdf", filename) }
+func (k *TableRenderer) ExportToMarkdown(filename string) { k.exportToFile("markdown", filename) }
 
 func GetTableScreen(config FormConfig, customStyles map[string]lipgloss.Color) string {
 	k := NewTableRenderer(config, customStyles)
@@ -504,6 +757,21 @@ func StartTableScreen(config FormConfig, customStyles map[string]lipgloss.Color)
 	return nil
 }
 
+// StartTableScreenWithData runs the table screen using the handler as its data source.
+func StartTableScreenWithData(handler TableDataHandler, opts TableOptions) error {
+	k := NewTableRendererFromHandler(handler, opts)
+
+	p := tea.NewProgram(k, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		logz.Error("Error running table screen: "+err.Error(), map[string]interface{}{
+			"context": "StartTableScreenWithData",
+			"title":   opts.Title,
+		})
+		return err
+	}
+	return nil
+}
+
 func NavigateAndExecuteTable(config FormConfig, customStyles map[string]lipgloss.Color) error {
 	k := NewTableRenderer(config, customStyles)
 
@@ -0,0 +1,248 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// searchDelay is how long the search waits for the next key before ranking the rows, so typing a
+// query rescans them once rather than on every key.
+const searchDelay = 150 * time.Millisecond
+
+// searchMatchStyle marks the matched characters of a cell.
+var searchMatchStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+
+// tableSearchMsg runs the search typed so far, unless a newer key replaced it.
+type tableSearchMsg struct{ id int }
+
+// searchMatch is a row matching the search, by its index among the filtered rows.
+type searchMatch struct {
+	row   int
+	score int
+}
+
+// fuzzyMatch matches the characters of query, in order, against text, ignoring case. It returns
+// the score of the best match, higher for characters that are consecutive or start a word, and the
+// rune positions of the matched characters in text. ok is false when text does not contain the
+// query.
+func fuzzyMatch(text, query string) (score int, positions []int, ok bool) {
+	if query == "" {
+		return 0, nil, false
+	}
+	runes := lowerRunes(text)
+	needle := lowerRunes(query)
+	best := -1
+	for start := range runes {
+		if runes[start] != needle[0] {
+			continue
+		}
+		s, pos := fuzzyScore(runes, needle, start)
+		if pos != nil && s > best {
+			best, positions = s, pos
+		}
+	}
+	if positions == nil {
+		return 0, nil, false
+	}
+	return best, positions, true
+}
+
+// lowerRunes lowers the case of every rune of s on its own, so the positions stay those of s even
+// for runes whose lowercase form is longer.
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// fuzzyScore matches needle greedily in runes from start and scores the match.
+func fuzzyScore(runes, needle []rune, start int) (int, []int) {
+	positions := make([]int, 0, len(needle))
+	score := 0
+	i := start
+	for _, r := range needle {
+		for i < len(runes) && runes[i] != r {
+			i++
+		}
+		if i == len(runes) {
+			return 0, nil
+		}
+		score += 1
+		switch {
+		case len(positions) > 0 && positions[len(positions)-1] == i-1:
+			score += 5
+		case i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]):
+			score += 3
+		}
+		positions = append(positions, i)
+		i++
+	}
+	if start == 0 {
+		score += 2
+	}
+	// the shorter the stretch of text the match spans, the better
+	score -= (positions[len(positions)-1] - positions[0] + 1 - len(needle)) / 2
+	return score, positions
+}
+
+// Search highlights the characters of the filtered rows matching the query and selects the best
+// ranked row. An empty query ends the search.
+func (k *TableRenderer) Search(query string) {
+	k.search, k.searchInput = query, query
+	k.searchVersion = -1
+	k.searchPos = 0
+	k.invalidate()
+	k.jumpToMatch(0)
+}
+
+// SearchMatches returns how many rows match the search.
+func (k *TableRenderer) SearchMatches() int {
+	k.updateSearch()
+	return len(k.searchMatches)
+}
+
+// NextMatch selects the next row matching the search, by rank, or the previous one when delta is
+// negative, wrapping around.
+func (k *TableRenderer) NextMatch(delta int) {
+	k.updateSearch()
+	if len(k.searchMatches) == 0 {
+		return
+	}
+	k.jumpToMatch((k.searchPos + delta + len(k.searchMatches)) % len(k.searchMatches))
+}
+
+// jumpToMatch selects the match at position i of the ranking and shows its page.
+func (k *TableRenderer) jumpToMatch(i int) {
+	k.updateSearch()
+	if i >= len(k.searchMatches) {
+		return
+	}
+	k.searchPos = i
+	k.selectedRow = k.searchMatches[i].row
+	k.page = k.selectedRow / k.pageSize
+}
+
+// updateSearch ranks the filtered rows against the search, once per change of the data.
+func (k *TableRenderer) updateSearch() {
+	if k.searchVersion == k.dataVersion {
+		return
+	}
+	k.searchVersion = k.dataVersion
+	k.searchMatches = k.searchMatches[:0]
+	k.searchHits = make(map[int]map[int]bool)
+	if k.search == "" {
+		return
+	}
+	for offset, total := 0, k.RowCount(); offset < total; offset += providerChunkSize {
+		for i, row := range k.RowsAt(offset, providerChunkSize) {
+			if len(row) == 0 || k.isGroupHeader(row) {
+				continue
+			}
+			best, hits := -1, make(map[int]bool)
+			for col, cell := range row {
+				if !k.visibleCols[cellAt(k.headers, col)] {
+					continue
+				}
+				if score, _, ok := fuzzyMatch(cell, k.search); ok {
+					hits[col] = true
+					best = max(best, score)
+				}
+			}
+			if best >= 0 {
+				k.searchMatches = append(k.searchMatches, searchMatch{row: offset + i, score: best})
+				k.searchHits[offset+i] = hits
+			}
+		}
+	}
+	slices.SortStableFunc(k.searchMatches, func(a, b searchMatch) int { return b.score - a.score })
+	if k.searchPos >= len(k.searchMatches) {
+		k.searchPos = 0
+	}
+}
+
+// highlightMatches fits the cell of the filtered row at index i, in column col, to width and marks
+// its matched characters. The match is taken again on the fitted text, so characters cut from the
+// cell are not counted and the ellipsis is never marked.
+func (k *TableRenderer) highlightMatches(i, col int, cell string, width int) string {
+	kept, cut := cutCell(cell, width)
+	if k.search == "" || !k.searchHits[i][col] {
+		return kept + cellPadding(kept, cut, width)
+	}
+	_, positions, _ := fuzzyMatch(kept, k.search)
+	var b, run strings.Builder
+	for pos, r := range []rune(kept) {
+		if !slices.Contains(positions, pos) {
+			b.WriteString(searchMatchStyle.Render(run.String()))
+			run.Reset()
+			b.WriteRune(r)
+			continue
+		}
+		run.WriteRune(r)
+	}
+	b.WriteString(searchMatchStyle.Render(run.String()))
+	return b.String() + cellPadding(kept, cut, width)
+}
+
+// updateSearchInput handles a key while the search query is typed. The query is ranked once no key
+// followed it for searchDelay, selecting the best match; enter runs it at once and keeps it for
+// n/N, and esc ends the search.
+func (k *TableRenderer) updateSearchInput(msg tea.KeyMsg) tea.Cmd {
+	query := k.searchInput
+	switch msg.Type {
+	case tea.KeyEnter:
+		// leave the filter too, so n and N jump through the matches
+		k.searching, k.filterFocused = false, false
+		if k.searchInput != k.search {
+			k.Search(k.searchInput)
+		}
+		return nil
+	case tea.KeyEsc:
+		k.searching = false
+		k.Search("")
+		return nil
+	case tea.KeyBackspace:
+		if query == "" {
+			k.searching = false
+			return nil
+		}
+		runes := []rune(query)
+		query = string(runes[:len(runes)-1])
+	case tea.KeyRunes, tea.KeySpace:
+		if msg.Alt {
+			return nil
+		}
+		query += string(msg.Runes)
+	default:
+		return nil
+	}
+	k.searchInput = query
+	k.searchID++
+	id := k.searchID
+	return tea.Tick(searchDelay, func(time.Time) tea.Msg {
+		return tableSearchMsg{id: id}
+	})
+}
+
+// searchStatus describes the search for the footer, or "" when there is none.
+func (k *TableRenderer) searchStatus() string {
+	if !k.searching && k.search == "" {
+		return ""
+	}
+	count := k.SearchMatches()
+	position := 0
+	if count > 0 {
+		position = k.searchPos + 1
+	}
+	if k.searching {
+		return fmt.Sprintf("  Search: /%s▏ (%d/%d)", k.searchInput, position, count)
+	}
+	return fmt.Sprintf("  Search: %s (%d/%d, n/N: next/previous)", k.search, position, count)
+}
@@ -0,0 +1,273 @@
+package components
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/faelmori/logz"
+)
+
+// markedRowStyle highlights the rows marked for a bulk action.
+var markedRowStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FDFF90")).Background(lipgloss.Color("#3A3A00"))
+
+// TableAction is a named operation on table rows, offered in the action menu.
+type TableAction struct {
+	Name string
+	// Run receives the marked rows, or the row under the cursor when none is marked.
+	Run func(rows [][]string) error
+	// Terminal hands the terminal over to Run while it executes, for actions that print or prompt,
+	// such as commands run with sudo.
+	Terminal bool
+	// Confirm lists the rows the action will run on and asks before running it, for actions that
+	// cannot be undone.
+	Confirm bool
+}
+
+// actionConfirmRows is the number of rows the confirmation lists before summing up the rest.
+const actionConfirmRows = 8
+
+// actionMenu is the state of the action menu overlay. rows holds the rows an action waiting for
+// confirmation will run on, nil while choosing the action.
+type actionMenu struct {
+	cursor int
+	rows   [][]string
+}
+
+// tableActionMsg reports the outcome of an action run on rows of the table.
+type tableActionMsg struct {
+	name string
+	rows int
+	err  error
+}
+
+// actionExec runs an action as a tea.ExecCommand, so the program releases the terminal meanwhile.
+// The action writes to the process standard streams itself.
+type actionExec struct {
+	run func() error
+}
+
+func (a actionExec) Run() error          { return a.run() }
+func (a actionExec) SetStdin(io.Reader)  {}
+func (a actionExec) SetStdout(io.Writer) {}
+func (a actionExec) SetStderr(io.Writer) {}
+
+// rowKey identifies a row by its content, so marks survive sorting, filtering and refreshes.
+// Rows with identical cells share their mark.
+func rowKey(row []string) string {
+	return strings.Join(row, "\x1f")
+}
+
+// markKey returns the key marking the row: the content of its handler row, so edited rows keep
+// their marks until the edits are committed.
+func (k *TableRenderer) markKey(row []string) string {
+	if len(row) > 0 {
+		if index, ok := k.workingRows[&row[0]]; ok {
+			return rowKey(k.source[index])
+		}
+	}
+	return rowKey(row)
+}
+
+// AddAction registers an action for the action menu.
+func (k *TableRenderer) AddAction(action TableAction) {
+	k.actions = append(k.actions, action)
+}
+
+// IsMarked reports whether the row is marked.
+func (k *TableRenderer) IsMarked(row []string) bool {
+	return row != nil && k.marked[k.markKey(row)]
+}
+
+// ToggleMark marks the filtered row at index i, or unmarks it when it already is.
+func (k *TableRenderer) ToggleMark(i int) {
+	row := k.rowAt(i)
+	if row == nil || k.isGroupHeader(row) {
+		return
+	}
+	if key := k.markKey(row); k.marked[key] {
+		delete(k.marked, key)
+	} else {
+		k.marked[key] = true
+	}
+}
+
+// MarkAll marks every row that passes the filter, including the rows of collapsed groups. When
+// they all are marked already, the marks are cleared instead.
+func (k *TableRenderer) MarkAll() {
+	var keys []string
+	all := true
+	k.eachDataRow(func(row []string) {
+		key := k.markKey(row)
+		all = all && k.marked[key]
+		keys = append(keys, key)
+	})
+	for _, key := range keys {
+		if all {
+			delete(k.marked, key)
+		} else {
+			k.marked[key] = true
+		}
+	}
+}
+
+// ClearMarks unmarks every row.
+func (k *TableRenderer) ClearMarks() {
+	clear(k.marked)
+}
+
+// SelectedRows returns the marked rows that pass the filter, in their current order, or the row
+// under the cursor when none is marked. Marked rows hidden by the filter are left out. A group
+// header under the cursor selects the rows of the group.
+func (k *TableRenderer) SelectedRows() [][]string {
+	var rows [][]string
+	if len(k.marked) > 0 {
+		k.eachDataRow(func(row []string) {
+			if k.IsMarked(row) {
+				rows = append(rows, row)
+			}
+		})
+		return rows
+	}
+	if g := k.groupAt(k.selectedRow); g != nil {
+		return append(rows, g.rows...)
+	}
+	if row := k.rowAt(k.selectedRow); row != nil {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// markRange extends the marks from the cursor row to the row in the given direction.
+func (k *TableRenderer) markRange(direction string) {
+	if k.selectedRow < 0 {
+		_ = k.RowsNavigate(direction)
+	}
+	if row := k.rowAt(k.selectedRow); row != nil && !k.isGroupHeader(row) {
+		k.marked[k.markKey(row)] = true
+	}
+	_ = k.RowsNavigate(direction)
+	if row := k.rowAt(k.selectedRow); row != nil && !k.isGroupHeader(row) {
+		k.marked[k.markKey(row)] = true
+	}
+}
+
+// selectionStatus describes the marked rows for the footer, or "" when none is marked.
+func (k *TableRenderer) selectionStatus() string {
+	if len(k.marked) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  Marked: %d", len(k.marked))
+}
+
+// OpenActionMenu shows the action menu, or a warning when no action is registered.
+func (k *TableRenderer) OpenActionMenu() tea.Cmd {
+	if len(k.actions) == 0 {
+		return k.notify(Warning, "No actions available for this table")
+	}
+	k.actionMenu = &actionMenu{}
+	return nil
+}
+
+// updateActionMenu handles a key while the action menu is open.
+func (k *TableRenderer) updateActionMenu(msg tea.KeyMsg) tea.Cmd {
+	m := k.actionMenu
+	if m.rows != nil {
+		switch msg.String() {
+		case "y", "enter":
+			k.actionMenu = nil
+			return k.runAction(k.actions[m.cursor], m.rows)
+		case "n", "esc":
+			m.rows = nil
+		}
+		return nil
+	}
+	switch msg.String() {
+	case "esc", "ctrl+r":
+		k.actionMenu = nil
+	case "up":
+		m.cursor = max(m.cursor-1, 0)
+	case "down":
+		m.cursor = min(m.cursor+1, len(k.actions)-1)
+	case "enter":
+		action := k.actions[m.cursor]
+		rows := k.SelectedRows()
+		if len(rows) == 0 {
+			k.actionMenu = nil
+			return k.notify(Warning, action.Name+": no rows selected")
+		}
+		if action.Confirm {
+			m.rows = rows
+			return nil
+		}
+		k.actionMenu = nil
+		return k.runAction(action, rows)
+	}
+	return nil
+}
+
+// runAction runs the action on the rows in the background and reports the outcome as a
+// tableActionMsg.
+func (k *TableRenderer) runAction(action TableAction, rows [][]string) tea.Cmd {
+	if action.Terminal {
+		return tea.Exec(actionExec{run: func() error { return action.Run(rows) }}, func(err error) tea.Msg {
+			return tableActionMsg{name: action.Name, rows: len(rows), err: err}
+		})
+	}
+	return func() tea.Msg {
+		return tableActionMsg{name: action.Name, rows: len(rows), err: action.Run(rows)}
+	}
+}
+
+// actionDone reports a finished action, clears the marks and reloads the data, which the action
+// may have changed.
+func (k *TableRenderer) actionDone(msg tableActionMsg) tea.Cmd {
+	if msg.err != nil {
+		logz.Error("Error running table action: "+msg.err.Error(), map[string]interface{}{
+			"context": "TableAction",
+			"action":  msg.name,
+			"rows":    msg.rows,
+		})
+		return k.notify(Error, fmt.Sprintf("%s failed: %s", msg.name, msg.err.Error()))
+	}
+	k.ClearMarks()
+	k.Refresh()
+	return k.notify(Info, fmt.Sprintf("%s: done for %d rows", msg.name, msg.rows))
+}
+
+// actionMenuView renders the action menu overlay, or the confirmation of the chosen action.
+func (k *TableRenderer) actionMenuView() string {
+	if k.actionMenu.rows != nil {
+		return k.actionConfirmView()
+	}
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Actions (%d rows)", len(k.SelectedRows()))) + "\n\n")
+	for i, action := range k.actions {
+		if i == k.actionMenu.cursor {
+			b.WriteString(focusedStyle.Render("> "+action.Name) + "\n")
+		} else {
+			b.WriteString("  " + action.Name + "\n")
+		}
+	}
+	b.WriteString("\n" + helpStyle.Render("up/down: choose • enter: run • esc: close"))
+	return k.overlay(b.String())
+}
+
+// actionConfirmView asks to confirm the chosen action, listing the first cell of the rows it will
+// run on.
+func (k *TableRenderer) actionConfirmView() string {
+	rows := k.actionMenu.rows
+	var b strings.Builder
+	title := fmt.Sprintf("%s: %d rows?", k.actions[k.actionMenu.cursor].Name, len(rows))
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(title) + "\n\n")
+	for _, row := range rows[:min(len(rows), actionConfirmRows)] {
+		b.WriteString("  " + cellAt(row, 0) + "\n")
+	}
+	if len(rows) > actionConfirmRows {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  ... and %d more", len(rows)-actionConfirmRows)) + "\n")
+	}
+	b.WriteString("\n" + helpStyle.Render("y/enter: run • n/esc: back"))
+	return k.overlay(b.String())
+}
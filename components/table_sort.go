@@ -0,0 +1,148 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+
+	. "github.com/faelmori/xtui/types"
+)
+
+// SortKey is one entry of the table sort stack. Column is the header name of the sorted column.
+type SortKey struct {
+	Column string `json:"column" yaml:"column"`
+	Asc    bool   `json:"asc" yaml:"asc"`
+}
+
+// SetColumnType sets the type hint used to sort the column with the given header.
+func (k *TableRenderer) SetColumnType(header string, typ ColumnType) {
+	k.columnTypes[header] = typ
+	k.SortRows()
+}
+
+// ColumnType returns the type hint of the column with the given header, defaulting to ColumnString.
+func (k *TableRenderer) ColumnType(header string) ColumnType {
+	if typ, ok := k.columnTypes[header]; ok {
+		return typ
+	}
+	return ColumnString
+}
+
+// SortKeys returns a copy of the current sort stack, primary key first.
+func (k *TableRenderer) SortKeys() []SortKey {
+	return slices.Clone(k.sortKeys)
+}
+
+// SetSortKeys replaces the sort stack. Keys that name unknown columns are dropped.
+func (k *TableRenderer) SetSortKeys(keys []SortKey) {
+	sortKeys := make([]SortKey, 0, len(keys))
+	for _, key := range keys {
+		known := slices.Contains(k.headers, key.Column)
+		repeated := slices.ContainsFunc(sortKeys, func(s SortKey) bool { return s.Column == key.Column })
+		if known && !repeated {
+			sortKeys = append(sortKeys, key)
+		}
+	}
+	k.sortKeys = sortKeys
+	k.SortRows()
+}
+
+// SortBy makes the column the only sort key. When it already is the primary key, its direction is
+// toggled instead.
+func (k *TableRenderer) SortBy(header string) {
+	if len(k.sortKeys) > 0 && k.sortKeys[0].Column == header {
+		k.sortKeys = []SortKey{{Column: header, Asc: !k.sortKeys[0].Asc}}
+	} else {
+		k.sortKeys = []SortKey{{Column: header, Asc: true}}
+	}
+	k.SortRows()
+}
+
+// ToggleSortKey adds the column to the end of the sort stack, or removes it when already present.
+func (k *TableRenderer) ToggleSortKey(header string) {
+	if i := k.sortKeyIndex(header); i >= 0 {
+		k.sortKeys = slices.Delete(k.sortKeys, i, i+1)
+	} else {
+		k.sortKeys = append(k.sortKeys, SortKey{Column: header, Asc: true})
+	}
+	k.SortRows()
+}
+
+// ToggleSortDirection flips the direction of the column in the sort stack, if it is sorted at all.
+func (k *TableRenderer) ToggleSortDirection(header string) {
+	if i := k.sortKeyIndex(header); i >= 0 {
+		k.sortKeys[i].Asc = !k.sortKeys[i].Asc
+		k.SortRows()
+	}
+}
+
+func (k *TableRenderer) sortKeyIndex(header string) int {
+	return slices.IndexFunc(k.sortKeys, func(key SortKey) bool { return key.Column == header })
+}
+
+// SortRows orders the filtered rows by every key of the sort stack, keeping the original order for
+// rows that compare equal.
+func (k *TableRenderer) SortRows() {
+	type resolvedKey struct {
+		col int
+		asc bool
+		typ ColumnType
+	}
+	if k.isLazy() && (len(k.sortKeys) > 0 || k.groupBy != "") {
+		k.loadFilteredRows()
+	}
+	var keys []resolvedKey
+	for _, key := range k.sortKeys {
+		if col := slices.Index(k.headers, key.Column); col >= 0 {
+			keys = append(keys, resolvedKey{col: col, asc: key.Asc, typ: k.ColumnType(key.Column)})
+		}
+	}
+	if len(keys) > 0 {
+		slices.SortStableFunc(k.filteredRows, func(a, b []string) int {
+			for _, key := range keys {
+				c := CompareCells(key.typ, cellAt(a, key.col), cellAt(b, key.col))
+				if c == 0 {
+					continue
+				}
+				if !key.asc {
+					return -c
+				}
+				return c
+			}
+			return 0
+		})
+	}
+	k.summarize()
+	k.invalidate()
+	k.syncHeaders()
+	k.syncTableRows()
+}
+
+// headerLabels returns the headers decorated with the sort direction and, when sorting by more
+// than one column, the position of each column in the sort stack.
+func (k *TableRenderer) headerLabels() []string {
+	labels := make([]string, len(k.headers))
+	for i, header := range k.headers {
+		labels[i] = header
+		idx := k.sortKeyIndex(header)
+		if idx < 0 {
+			continue
+		}
+		arrow := "▲"
+		if !k.sortKeys[idx].Asc {
+			arrow = "▼"
+		}
+		if len(k.sortKeys) > 1 {
+			labels[i] = fmt.Sprintf("%s %s%d", header, arrow, idx+1)
+		} else {
+			labels[i] = header + " " + arrow
+		}
+	}
+	return labels
+}
+
+func cellAt(row []string, col int) string {
+	if col < 0 || col >= len(row) {
+		return ""
+	}
+	return row[col]
+}
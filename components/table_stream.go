@@ -0,0 +1,256 @@
+package components
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/faelmori/logz"
+	. "github.com/faelmori/xtui/types"
+)
+
+const (
+	// streamBatchSize is the most rows appended to a streaming table at once.
+	streamBatchSize = 1024
+	// streamBatchWait is how long a streaming table collects rows before drawing them.
+	streamBatchWait = 100 * time.Millisecond
+)
+
+// Input formats read by ReadTableStream.
+const (
+	StreamAuto       = "auto"
+	StreamCSV        = "csv"
+	StreamTSV        = "tsv"
+	StreamWhitespace = "ws"
+)
+
+// tableRowsMsg carries rows received from the stream of a streaming table.
+type tableRowsMsg struct {
+	rows   [][]string
+	closed bool
+}
+
+// NewStreamingTableRenderer creates a table that starts empty and appends the rows received on
+// the channel while it runs. The filter and the sort stack apply to the new rows as they arrive.
+func NewStreamingTableRenderer(headers []string, rows <-chan []string, opts TableOptions) *TableRenderer {
+	k := newTableRenderer(FormConfig{Title: opts.Title}, headers, make([][]string, 0), opts.CustomStyles)
+	k.stream = rows
+	k.applyOptions(opts)
+	if k.groupBy != "" || len(k.aggregations) > 0 {
+		k.SortRows()
+	}
+	k.restoreView(opts.View)
+	return k
+}
+
+// StartStreamingTable runs a table fed by the rows received on the channel, such as the lines of
+// a command output. Keys are read from the terminal, so the standard input can be the data source.
+func StartStreamingTable(headers []string, rows <-chan []string, opts TableOptions) error {
+	k := NewStreamingTableRenderer(headers, rows, opts)
+
+	p := tea.NewProgram(k, tea.WithAltScreen(), tea.WithInputTTY())
+	if _, err := p.Run(); err != nil {
+		logz.Error("Error running table screen: "+err.Error(), map[string]interface{}{
+			"context": "StartStreamingTable",
+			"title":   opts.Title,
+		})
+		return err
+	}
+	return nil
+}
+
+// waitForRows returns a command that waits for rows on the stream and collects the rows that
+// follow shortly after, so a fast stream is drawn in batches.
+func (k *TableRenderer) waitForRows() tea.Cmd {
+	if k.stream == nil {
+		return nil
+	}
+	stream := k.stream
+	return func() tea.Msg {
+		row, ok := <-stream
+		if !ok {
+			return tableRowsMsg{closed: true}
+		}
+		rows := [][]string{row}
+		timeout := time.After(streamBatchWait)
+		for len(rows) < streamBatchSize {
+			select {
+			case row, ok := <-stream:
+				if !ok {
+					return tableRowsMsg{rows: rows, closed: true}
+				}
+				rows = append(rows, row)
+			case <-timeout:
+				return tableRowsMsg{rows: rows}
+			}
+		}
+		return tableRowsMsg{rows: rows}
+	}
+}
+
+// AppendRows adds rows to the table, keeping the filter, the sort stack and the grouping applied.
+// When the table follows its tail, the last page is shown.
+func (k *TableRenderer) AppendRows(rows [][]string) {
+	if k.provider != nil || len(rows) == 0 {
+		return
+	}
+	k.rows = append(k.rows, rows...)
+	k.source = append(k.source[:len(k.source):len(k.source)], rows...)
+	// the filtered rows may share their array with the source rows, so they are never grown in place
+	filtered := k.filteredRows[:len(k.filteredRows):len(k.filteredRows)]
+	k.filteredRows = append(filtered, k.filterRows(rows)...)
+	for _, row := range rows {
+		k.fitColumnsTo(row)
+	}
+	if len(k.sortKeys) > 0 || k.groupBy != "" || len(k.aggregations) > 0 {
+		k.SortRows()
+	} else {
+		k.invalidate()
+		k.syncHeaders()
+	}
+	if k.follow {
+		k.followTail()
+	}
+}
+
+// Follow makes the table show its last page whenever rows are appended, or stops doing so.
+func (k *TableRenderer) Follow(follow bool) {
+	k.follow = follow
+	if follow {
+		k.followTail()
+	}
+}
+
+// followTail shows the last page and moves the selection, if any, to the last row.
+func (k *TableRenderer) followTail() {
+	count := k.RowCount()
+	if count == 0 {
+		return
+	}
+	k.page = (count - 1) / k.pageSize
+	if k.selectedRow >= 0 {
+		k.selectedRow = count - 1
+	}
+}
+
+// streamStatus describes the state of the stream for the footer, or "" when the table has none.
+func (k *TableRenderer) streamStatus() string {
+	switch {
+	case k.stream == nil && !k.streamClosed:
+		return ""
+	case k.streamClosed:
+		return fmt.Sprintf("  Rows: %d (end of stream)", len(k.rows))
+	case k.follow:
+		return fmt.Sprintf("  Rows: %d (following)", len(k.rows))
+	}
+	return fmt.Sprintf("  Rows: %d (end: follow)", len(k.rows))
+}
+
+// ReadTableStream reads delimited rows from r in the background and sends them on the returned
+// channel, which is closed at the end of the input. format is one of StreamCSV, StreamTSV,
+// StreamWhitespace or StreamAuto, which picks one from the first line. Whitespace separated
+// columns are split on runs of spaces, with the last column taking the rest of the line, as in the
+// output of ps or kubectl. When headers is empty, the first row holds the headers.
+func ReadTableStream(r io.Reader, format string, headers []string) ([]string, <-chan []string, error) {
+	br := bufio.NewReader(r)
+	if format == "" || format == StreamAuto {
+		// the first line is read whole, so a slow stream is not waited on to fill a buffer
+		first, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, nil, err
+		}
+		format = detectStreamFormat(first)
+		br = bufio.NewReader(io.MultiReader(strings.NewReader(first), br))
+	}
+
+	var next func() ([]string, error)
+	switch format {
+	case StreamCSV, StreamTSV:
+		reader := csv.NewReader(br)
+		if format == StreamTSV {
+			reader.Comma = '\t'
+		}
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		next = reader.Read
+	case StreamWhitespace:
+		next = func() ([]string, error) {
+			for {
+				line, err := br.ReadString('\n')
+				if strings.TrimSpace(line) != "" {
+					return splitFields(line, len(headers)), nil
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown stream format %q", format)
+	}
+
+	if len(headers) == 0 {
+		first, err := next()
+		if err != nil {
+			if err == io.EOF {
+				return nil, nil, fmt.Errorf("no header line in input")
+			}
+			return nil, nil, err
+		}
+		headers = first
+	}
+
+	rows := make(chan []string)
+	go func() {
+		defer close(rows)
+		for {
+			row, err := next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				logz.Error("Error reading table stream: "+err.Error(), map[string]interface{}{
+					"context": "ReadTableStream",
+					"format":  format,
+				})
+				return
+			}
+			rows <- row
+		}
+	}()
+	return headers, rows, nil
+}
+
+// detectStreamFormat guesses the format of the input from its first line.
+func detectStreamFormat(line string) string {
+	switch {
+	case strings.Contains(line, "\t"):
+		return StreamTSV
+	case strings.Contains(line, ","):
+		return StreamCSV
+	}
+	return StreamWhitespace
+}
+
+// splitFields splits a line on runs of whitespace into at most n fields, the last one keeping the
+// rest of the line. n <= 0 splits every field.
+func splitFields(line string, n int) []string {
+	line = strings.TrimSpace(line)
+	if n <= 0 {
+		return strings.Fields(line)
+	}
+	fields := make([]string, 0, n)
+	for len(fields) < n-1 {
+		i := strings.IndexAny(line, " \t")
+		if i < 0 {
+			break
+		}
+		fields = append(fields, line[:i])
+		line = strings.TrimLeft(line[i:], " \t")
+	}
+	return append(fields, line)
+}
@@ -0,0 +1,224 @@
+package components
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/faelmori/logz"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	tableBaseStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	tableHeaderStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Bold(true)
+	headerCursorStyle = tableHeaderStyle.Foreground(lipgloss.Color("#01BE85")).Underline(true)
+	// selectedRowStyle highlights the row under the cursor.
+	selectedRowStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#01BE85")).Background(lipgloss.Color("#00432F"))
+)
+
+// CellStyle is the look a style rule gives to the cells it matches. Colors are lipgloss colors,
+// such as "#FF7698" or "196". Properties left empty or false keep the look of lower priority rules.
+type CellStyle struct {
+	Foreground string `json:"foreground,omitempty" yaml:"foreground,omitempty"`
+	Background string `json:"background,omitempty" yaml:"background,omitempty"`
+	Bold       bool   `json:"bold,omitempty" yaml:"bold,omitempty"`
+	Italic     bool   `json:"italic,omitempty" yaml:"italic,omitempty"`
+	Underline  bool   `json:"underline,omitempty" yaml:"underline,omitempty"`
+	Faint      bool   `json:"faint,omitempty" yaml:"faint,omitempty"`
+}
+
+func (s CellStyle) lipglossStyle() lipgloss.Style {
+	style := lipgloss.NewStyle()
+	if s.Foreground != "" {
+		style = style.Foreground(lipgloss.Color(s.Foreground))
+	}
+	if s.Background != "" {
+		style = style.Background(lipgloss.Color(s.Background))
+	}
+	if s.Bold {
+		style = style.Bold(true)
+	}
+	if s.Italic {
+		style = style.Italic(true)
+	}
+	if s.Underline {
+		style = style.Underline(true)
+	}
+	if s.Faint {
+		style = style.Faint(true)
+	}
+	return style
+}
+
+// StyleRule styles the cells of the rows that match a condition.
+type StyleRule struct {
+	// When is a condition in the filter bar syntax, such as `Status=residual` or `Size>1G`. Every
+	// term must hold; an empty condition matches every row.
+	When string `json:"when,omitempty" yaml:"when,omitempty"`
+	// Match is an additional condition checked in code. It is not loaded from YAML.
+	Match func(row []string) bool `json:"-" yaml:"-"`
+	// Column is the header of the styled column. An empty column styles the whole row.
+	Column string    `json:"column,omitempty" yaml:"column,omitempty"`
+	Style  CellStyle `json:"style" yaml:"style"`
+	// Priority decides between rules styling the same cell: properties set by a higher priority
+	// rule win, and on equal priority the rule added first wins.
+	Priority int `json:"priority,omitempty" yaml:"priority,omitempty"`
+}
+
+// styleRule is a StyleRule resolved against the table headers.
+type styleRule struct {
+	StyleRule
+	expr  *FilterExpr
+	col   int
+	style lipgloss.Style
+}
+
+// LoadStyleRules reads a YAML list of style rules, such as:
+//
+//   - when: Status=residual
+//     column: Status
+//     style: {foreground: "#FF7698", bold: true}
+//   - when: Size>1G
+//     column: Size
+//     style: {foreground: "#FDFF90"}
+//     priority: 10
+func LoadStyleRules(path string) ([]StyleRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []StyleRule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// StyleRules returns the style rules of the table, in the order they were added.
+func (k *TableRenderer) StyleRules() []StyleRule {
+	return slices.Clone(k.styleRules)
+}
+
+// SetStyleRules replaces the style rules of the table. Nothing changes when a rule is invalid.
+func (k *TableRenderer) SetStyleRules(rules []StyleRule) error {
+	compiled, err := k.compileStyleRules(rules)
+	if err != nil {
+		return err
+	}
+	k.styleRules = slices.Clone(rules)
+	k.compiledRules = compiled
+	k.invalidate()
+	return nil
+}
+
+// AddStyleRule adds a style rule to the table.
+func (k *TableRenderer) AddStyleRule(rule StyleRule) error {
+	return k.SetStyleRules(append(slices.Clone(k.styleRules), rule))
+}
+
+// applyStyleRules sets the rules given in the options, or resolves them again after the headers
+// changed. Invalid rules are logged and left out.
+func (k *TableRenderer) applyStyleRules(rules []StyleRule) {
+	k.styleRules = nil
+	k.compiledRules = nil
+	for _, rule := range rules {
+		if err := k.AddStyleRule(rule); err != nil {
+			logz.Error("Error adding table style rule: "+err.Error(), map[string]interface{}{
+				"context": "StyleRules",
+				"when":    rule.When,
+				"column":  rule.Column,
+			})
+		}
+	}
+}
+
+// compileStyleRules resolves the rules against the headers, ordered from the highest priority.
+func (k *TableRenderer) compileStyleRules(rules []StyleRule) ([]*styleRule, error) {
+	compiled := make([]*styleRule, 0, len(rules))
+	for i, rule := range rules {
+		expr, err := ParseFilter(rule.When, k.headers)
+		if err != nil {
+			return nil, fmt.Errorf("style rule %d: %w", i+1, err)
+		}
+		col := -1
+		if rule.Column != "" {
+			if col = slices.Index(k.headers, rule.Column); col < 0 {
+				return nil, fmt.Errorf("style rule %d: unknown column %q", i+1, rule.Column)
+			}
+		}
+		compiled = append(compiled, &styleRule{StyleRule: rule, expr: expr, col: col, style: rule.Style.lipglossStyle()})
+	}
+	slices.SortStableFunc(compiled, func(a, b *styleRule) int { return b.Priority - a.Priority })
+	return compiled, nil
+}
+
+// matchingRules returns the rules that match the row, from the highest priority. Matches are
+// cached until the data changes, since the style of every cell of the row needs them.
+func (k *TableRenderer) matchingRules(row []string) []*styleRule {
+	if len(k.compiledRules) == 0 || len(row) == 0 {
+		return nil
+	}
+	if k.ruleVersion != k.dataVersion {
+		clear(k.ruleMatches)
+		k.ruleVersion = k.dataVersion
+	}
+	if rules, ok := k.ruleMatches[&row[0]]; ok {
+		return rules
+	}
+	var rules []*styleRule
+	for _, rule := range k.compiledRules {
+		if rule.expr.Match(row, k.columnTypeAt) && (rule.Match == nil || rule.Match(row)) {
+			rules = append(rules, rule)
+		}
+	}
+	k.ruleMatches[&row[0]] = rules
+	return rules
+}
+
+// cellStyle is the StyleFunc of the lipgloss table. row and col count the drawn rows and columns.
+// The style rules and the value colors give the look of a cell, and the selection, the marks and
+// the edits are layered on top, so a rule color stays visible on a selected row.
+func (k *TableRenderer) cellStyle(row, col int) lipgloss.Style {
+	// col counts the drawn columns; map it back to the column of the source rows
+	col = k.renderedColumn(col)
+	if row == table.HeaderRow {
+		if col == k.sortCursor {
+			return headerCursorStyle.Padding(0, 1)
+		}
+		return tableHeaderStyle.Padding(0, 1)
+	}
+	if row == len(k.windowRows) && k.footer != nil {
+		return footerStyle.Padding(0, 1)
+	}
+	if row < 0 || row >= len(k.windowRows) {
+		return tableBaseStyle.Padding(0, 1)
+	}
+	cells := k.windowRows[row]
+	if k.isGroupHeader(cells) {
+		return groupHeaderStyle.Padding(0, 1)
+	}
+
+	style := lipgloss.NewStyle()
+	for _, rule := range k.matchingRules(cells) {
+		if rule.col < 0 || rule.col == col {
+			style = style.Inherit(rule.style)
+		}
+	}
+	if color, ok := k.valueColors[cellAt(cells, col)]; ok {
+		style = style.Inherit(lipgloss.NewStyle().Foreground(color))
+	}
+
+	switch {
+	case k.selectedRow >= 0 && k.window.offset+row == k.selectedRow:
+		style = style.Background(selectedRowStyle.GetBackground()).Inherit(selectedRowStyle)
+	case k.IsMarked(cells):
+		style = style.Background(markedRowStyle.GetBackground()).Inherit(markedRowStyle)
+	}
+	if k.isEdited(cells, col) {
+		style = style.Inherit(editedCellStyle)
+	}
+	return style.Inherit(tableBaseStyle).Padding(0, 1)
+}
@@ -0,0 +1,389 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/faelmori/logz"
+	"github.com/faelmori/xtui/components/export"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultViewName is the name of the view opened when a table with an ID starts without one.
+const DefaultViewName = "default"
+
+// unsafeIDChars matches the characters of a table ID replaced in the name of its views file.
+var unsafeIDChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// TableView is a named snapshot of the state of a table: its filter, sort stack, columns, page
+// size and grouping.
+type TableView struct {
+	Name   string    `json:"name" yaml:"name"`
+	Filter string    `json:"filter,omitempty" yaml:"filter,omitempty"`
+	Sort   []SortKey `json:"sort,omitempty" yaml:"sort,omitempty"`
+	// Columns are the visible columns, in display order. Empty shows every column.
+	Columns []string `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Pinned  int      `json:"pinned,omitempty" yaml:"pinned,omitempty"`
+	// PageSize caps the rows shown per page; 0 fits the page to the screen.
+	PageSize     int                    `json:"page_size,omitempty" yaml:"page_size,omitempty"`
+	GroupBy      string                 `json:"group_by,omitempty" yaml:"group_by,omitempty"`
+	Aggregations map[string]Aggregation `json:"aggregations,omitempty" yaml:"aggregations,omitempty"`
+}
+
+// viewPicker is the state of the saved views overlay.
+type viewPicker struct {
+	views  []TableView
+	cursor int
+	name   textinput.Model
+	err    error
+}
+
+// TableViewsPath returns the YAML file holding the views of the table with the given ID, under
+// the configuration directory of the user.
+func TableViewsPath(id string) (string, error) {
+	if strings.TrimSpace(id) == "" {
+		return "", errors.New("the table has no ID to store views under")
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "xtui", "views", unsafeIDChars.ReplaceAllString(id, "_")+".yaml"), nil
+}
+
+// LoadTableViews reads the saved views of a table. A table without saved views has none.
+func LoadTableViews(id string) ([]TableView, error) {
+	path, err := TableViewsPath(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var views []TableView
+	if err := yaml.Unmarshal(data, &views); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return views, nil
+}
+
+// SaveTableViews replaces the saved views of a table. The file is replaced only once fully written,
+// so a failed save keeps the views saved before.
+func SaveTableViews(id string, views []TableView) error {
+	path, err := TableViewsPath(id)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(views)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return export.WriteFileAtomic(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// SaveTableView saves a view of a table, replacing the view with the same name.
+func SaveTableView(id string, view TableView) error {
+	views, err := LoadTableViews(id)
+	if err != nil {
+		return err
+	}
+	if i := slices.IndexFunc(views, func(v TableView) bool { return v.Name == view.Name }); i >= 0 {
+		views[i] = view
+	} else {
+		views = append(views, view)
+	}
+	return SaveTableViews(id, views)
+}
+
+// DeleteTableView removes a saved view of a table.
+func DeleteTableView(id, name string) error {
+	views, err := LoadTableViews(id)
+	if err != nil {
+		return err
+	}
+	return SaveTableViews(id, slices.DeleteFunc(views, func(v TableView) bool { return v.Name == name }))
+}
+
+// FindTableView returns the saved view of a table with the given name.
+func FindTableView(id, name string) (TableView, error) {
+	views, err := LoadTableViews(id)
+	if err != nil {
+		return TableView{}, err
+	}
+	if i := slices.IndexFunc(views, func(v TableView) bool { return v.Name == name }); i >= 0 {
+		return views[i], nil
+	}
+	names := make([]string, len(views))
+	for i, view := range views {
+		names[i] = view.Name
+	}
+	return TableView{}, fmt.Errorf("table %q has no view named %q (saved views: %s)", id, name, strings.Join(names, ", "))
+}
+
+// ID returns the identifier the views of the table are stored under.
+func (k *TableRenderer) ID() string {
+	return k.id
+}
+
+// CurrentView returns the name of the view last opened or saved, or "" when there is none.
+func (k *TableRenderer) CurrentView() string {
+	return k.currentView
+}
+
+// Snapshot returns the current state of the table as a view with the given name.
+func (k *TableRenderer) Snapshot(name string) TableView {
+	view := TableView{
+		Name:     name,
+		Filter:   k.filter,
+		Sort:     k.SortKeys(),
+		Pinned:   k.pinnedCols,
+		PageSize: k.pageLimit,
+		GroupBy:  k.groupBy,
+	}
+	if visible := k.VisibleColumns(); !slices.Equal(visible, k.headers) {
+		view.Columns = visible
+	}
+	if len(k.aggregations) > 0 {
+		view.Aggregations = make(map[string]Aggregation, len(k.aggregations))
+		for header, agg := range k.aggregations {
+			view.Aggregations[header] = agg
+		}
+	}
+	return view
+}
+
+// ApplyView restores the state saved in a view. Columns the table no longer has are ignored.
+func (k *TableRenderer) ApplyView(view TableView) {
+	if len(view.Columns) > 0 && slices.ContainsFunc(view.Columns, func(c string) bool { return slices.Contains(k.headers, c) }) {
+		for _, header := range k.headers {
+			k.visibleCols[header] = slices.Contains(view.Columns, header)
+		}
+	} else {
+		for _, header := range k.headers {
+			k.visibleCols[header] = true
+		}
+	}
+	k.columnOrder = nil
+	k.syncColumnOrder()
+	k.SetColumnOrder(view.Columns)
+	k.colOffset = 0
+	k.pinnedCols = min(max(view.Pinned, 0), len(k.columnOrder))
+
+	clear(k.aggregations)
+	for header, agg := range view.Aggregations {
+		if slices.Contains(k.headers, header) {
+			k.aggregations[header] = agg
+		}
+	}
+	k.groupBy = ""
+	if slices.Contains(k.headers, view.GroupBy) {
+		k.groupBy = view.GroupBy
+	}
+	clear(k.expanded)
+
+	k.sortKeys = slices.DeleteFunc(slices.Clone(view.Sort), func(key SortKey) bool {
+		return !slices.Contains(k.headers, key.Column)
+	})
+	k.pageLimit = max(view.PageSize, 0)
+	k.selectedRow = -1
+	k.page = 0
+	k.applySize()
+
+	k.filter = view.Filter
+	k.ApplyFilter()
+	if k.filterErr != nil {
+		k.loadFilteredRows()
+		k.SortRows()
+	}
+	k.currentView = view.Name
+}
+
+// SetPageSize caps the rows shown per page, which are otherwise fitted to the screen. 0 removes
+// the cap.
+func (k *TableRenderer) SetPageSize(n int) {
+	k.pageLimit = max(n, 0)
+	k.applySize()
+	k.syncTableRows()
+}
+
+// SaveView saves the current state of the table as a named view under the table ID.
+func (k *TableRenderer) SaveView(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("a view needs a name")
+	}
+	if err := SaveTableView(k.id, k.Snapshot(name)); err != nil {
+		return err
+	}
+	k.currentView = name
+	return nil
+}
+
+// OpenView restores the saved view of the table with the given name.
+func (k *TableRenderer) OpenView(name string) error {
+	view, err := FindTableView(k.id, name)
+	if err != nil {
+		return err
+	}
+	k.ApplyView(view)
+	return nil
+}
+
+// restoreView opens the view given in the options, or the default view when the table has one.
+// Errors are logged and shown below the table.
+func (k *TableRenderer) restoreView(name string) {
+	if err := k.openInitialView(name); err != nil {
+		k.logViewError(err)
+		k.notice = &Notification{Message: err.Error(), Type: Error}
+	}
+}
+
+// openInitialView opens the named view, or the default view when the name is empty. A missing
+// default view is not an error.
+func (k *TableRenderer) openInitialView(name string) error {
+	if k.id == "" {
+		if name != "" {
+			return fmt.Errorf("view %q: the table has no ID", name)
+		}
+		return nil
+	}
+	if name != "" {
+		return k.OpenView(name)
+	}
+	views, err := LoadTableViews(k.id)
+	if err != nil {
+		return err
+	}
+	if i := slices.IndexFunc(views, func(v TableView) bool { return v.Name == DefaultViewName }); i >= 0 {
+		k.ApplyView(views[i])
+	}
+	return nil
+}
+
+// OpenViewPicker shows the saved views of the table, to open, save or delete one.
+func (k *TableRenderer) OpenViewPicker() tea.Cmd {
+	if k.id == "" {
+		return k.notify(Warning, "Views: the table has no ID")
+	}
+	views, err := LoadTableViews(k.id)
+	k.logViewError(err)
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "view name"
+	input.Cursor.Style = focusedStyle
+	cmd := input.Focus()
+	k.viewPicker = &viewPicker{views: views, name: input, err: err}
+	if i := slices.IndexFunc(views, func(v TableView) bool { return v.Name == k.currentView }); i >= 0 {
+		k.viewPicker.cursor = i
+	}
+	return cmd
+}
+
+// updateViewPicker handles a key while the views overlay is open. A typed name saves the current
+// state under it; otherwise enter opens the view under the cursor.
+func (k *TableRenderer) updateViewPicker(msg tea.KeyMsg) tea.Cmd {
+	p := k.viewPicker
+	switch msg.String() {
+	case "esc", "f3":
+		k.viewPicker = nil
+	case "up":
+		p.cursor = max(p.cursor-1, 0)
+	case "down":
+		p.cursor = max(min(p.cursor+1, len(p.views)-1), 0)
+	case "ctrl+d":
+		if p.cursor < len(p.views) {
+			name := p.views[p.cursor].Name
+			p.err = DeleteTableView(k.id, name)
+			k.logViewError(p.err)
+			if p.err == nil {
+				p.views = slices.Delete(p.views, p.cursor, p.cursor+1)
+				p.cursor = max(min(p.cursor, len(p.views)-1), 0)
+				if k.currentView == name {
+					k.currentView = ""
+				}
+			}
+		}
+	case "enter":
+		if name := strings.TrimSpace(p.name.Value()); name != "" {
+			if err := k.SaveView(name); err != nil {
+				p.err = err
+				k.logViewError(err)
+				return nil
+			}
+			k.viewPicker = nil
+			return k.notify(Info, fmt.Sprintf("View %q saved", name))
+		}
+		if p.cursor < len(p.views) {
+			k.viewPicker = nil
+			k.ApplyView(p.views[p.cursor])
+		}
+	default:
+		var cmd tea.Cmd
+		p.name, cmd = p.name.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+// viewPickerView renders the saved views overlay.
+func (k *TableRenderer) viewPickerView() string {
+	p := k.viewPicker
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render("Views of "+k.id) + "\n\n")
+	if len(p.views) == 0 {
+		b.WriteString(blurredStyle.Render("  no saved views") + "\n")
+	}
+	for i, view := range p.views {
+		line := view.Name
+		if view.Filter != "" {
+			line += blurredStyle.Render("  " + view.Filter)
+		}
+		if i == p.cursor {
+			b.WriteString(focusedStyle.Render("> "+view.Name) + strings.TrimPrefix(line, view.Name) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	b.WriteString("\nSave as: " + p.name.View() + "\n")
+	if p.err != nil {
+		b.WriteString("\n" + errorStyle.Render(p.err.Error()) + "\n")
+	}
+	b.WriteString("\n" + helpStyle.Render("up/down + enter: open • name + enter: save • ctrl+d: delete • esc: close"))
+	return k.overlay(b.String())
+}
+
+func (k *TableRenderer) logViewError(err error) {
+	if err == nil {
+		return
+	}
+	logz.Error("Error in table views: "+err.Error(), map[string]interface{}{
+		"context": "TableViews",
+		"table":   k.id,
+	})
+}
+
+// viewStatus names the current view for the footer, or returns "" when there is none.
+func (k *TableRenderer) viewStatus() string {
+	if k.currentView == "" {
+		return ""
+	}
+	return "  View: " + k.currentView
+}
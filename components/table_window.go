@@ -0,0 +1,357 @@
+package components
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/faelmori/logz"
+	. "github.com/faelmori/xtui/types"
+)
+
+const (
+	// maxColumnWidth caps the width of a column; longer cells are truncated with an ellipsis.
+	maxColumnWidth = 48
+	// widthSampleSize is how many rows are measured to size the columns.
+	widthSampleSize = 256
+	// providerChunkSize is how many rows are read at a time when a provider has to be scanned.
+	providerChunkSize = 4096
+	// tableChromeLines is the number of screen lines used around the rows (filter bar, borders, footer).
+	tableChromeLines = 10
+)
+
+// tableWindow identifies the rows currently loaded into the lipgloss table.
+type tableWindow struct {
+	offset, limit, version int
+}
+
+// NewTableRendererFromProvider creates a table that reads its rows page by page from the provider,
+// so only the visible rows are ever formatted. Filtering or sorting scans the provider in chunks
+// and keeps only the matching rows in memory.
+func NewTableRendererFromProvider(provider TableRowProvider, opts TableOptions) *TableRenderer {
+	k := newTableRenderer(FormConfig{Title: opts.Title}, provider.GetHeaders(), nil, opts.CustomStyles)
+	k.provider = provider
+	k.applyOptions(opts)
+	k.filteredRows = nil
+	k.sampleColumnWidths()
+	if k.groupBy != "" || len(k.aggregations) > 0 {
+		k.SortRows()
+	} else {
+		k.invalidate()
+		k.syncHeaders()
+		k.syncTableRows()
+	}
+	k.restoreView(opts.View)
+	return k
+}
+
+// StartTableScreenWithProvider runs the table screen over a paged row provider.
+func StartTableScreenWithProvider(provider TableRowProvider, opts TableOptions) error {
+	k := NewTableRendererFromProvider(provider, opts)
+
+	p := tea.NewProgram(k, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		logz.Error("Error running table screen: "+err.Error(), map[string]interface{}{
+			"context": "StartTableScreenWithProvider",
+			"title":   opts.Title,
+		})
+		return err
+	}
+	return nil
+}
+
+// isLazy reports whether rows are read straight from the provider, with no filter or sort applied.
+func (k *TableRenderer) isLazy() bool {
+	return k.provider != nil && k.filteredRows == nil
+}
+
+// RowCount returns the number of rows that pass the current filter. When the rows are grouped, it
+// counts the group headers and the rows of the expanded groups.
+func (k *TableRenderer) RowCount() int {
+	if k.groupBy != "" {
+		return len(k.groupedRows)
+	}
+	if k.isLazy() {
+		return k.provider.Count()
+	}
+	return len(k.filteredRows)
+}
+
+// RowsAt returns up to limit filtered rows starting at offset.
+func (k *TableRenderer) RowsAt(offset, limit int) [][]string {
+	if offset < 0 || limit <= 0 {
+		return nil
+	}
+	if k.groupBy != "" {
+		if offset >= len(k.groupedRows) {
+			return nil
+		}
+		return k.groupedRows[offset:min(offset+limit, len(k.groupedRows))]
+	}
+	if k.isLazy() {
+		return k.provider.RowsAt(offset, limit)
+	}
+	if offset >= len(k.filteredRows) {
+		return nil
+	}
+	return k.filteredRows[offset:min(offset+limit, len(k.filteredRows))]
+}
+
+// sourceRows returns every row of the source data, unfiltered and in source order.
+func (k *TableRenderer) sourceRows() [][]string {
+	if k.provider != nil {
+		return k.provider.RowsAt(0, k.provider.Count())
+	}
+	return k.rows
+}
+
+// rowAt returns the filtered row at index i, or nil when it is out of range.
+func (k *TableRenderer) rowAt(i int) []string {
+	if i >= k.window.offset && i < k.window.offset+len(k.windowRows) {
+		return k.windowRows[i-k.window.offset]
+	}
+	if rows := k.RowsAt(i, 1); len(rows) > 0 {
+		return rows[0]
+	}
+	return nil
+}
+
+// loadFilteredRows rebuilds the filtered rows from the source data. Providers are left lazy while
+// there is nothing to filter or sort, otherwise they are scanned in chunks.
+func (k *TableRenderer) loadFilteredRows() {
+	if k.provider == nil {
+		k.filteredRows = k.filterRows(k.rows)
+		return
+	}
+	if (k.filterExpr == nil || len(k.filterExpr.Terms) == 0) && len(k.sortKeys) == 0 && k.groupBy == "" {
+		k.filteredRows = nil
+		return
+	}
+	rows := make([][]string, 0)
+	total := k.provider.Count()
+	for offset := 0; offset < total; offset += providerChunkSize {
+		chunk := k.provider.RowsAt(offset, providerChunkSize)
+		if len(chunk) == 0 {
+			break
+		}
+		rows = append(rows, k.filterRows(chunk)...)
+	}
+	k.filteredRows = rows
+}
+
+// invalidate marks the loaded window as stale after the filtered rows changed.
+func (k *TableRenderer) invalidate() {
+	k.dataVersion++
+}
+
+// syncTableRows loads the rows of the current page into the lipgloss table. Nothing is done when
+// the page and the data did not change since the last call.
+func (k *TableRenderer) syncTableRows() {
+	w := tableWindow{offset: k.page * k.pageSize, limit: k.pageSize, version: k.dataVersion}
+	if w == k.window && k.windowRows != nil {
+		return
+	}
+	k.window = w
+	k.windowRows = k.RowsAt(w.offset, w.limit)
+	if k.windowRows == nil {
+		k.windowRows = make([][]string, 0)
+	}
+	rows := k.formatRows(k.windowRows)
+	if k.search != "" {
+		for i, row := range rows {
+			for j, col := range k.renderedCols {
+				row[j] = k.highlightMatches(w.offset+i, col, cellAt(k.windowRows[i], col), k.columnWidth(col))
+			}
+		}
+	}
+	if k.footer != nil {
+		rows = append(rows, k.formatRows([][]string{k.footer})...)
+	}
+	k.kTb.ClearRows()
+	k.kTb = k.kTb.Rows(rows...)
+}
+
+// syncHeaders lays out the drawn columns and loads their decorated headers, fitted to the column
+// widths, into the lipgloss table.
+func (k *TableRenderer) syncHeaders() {
+	k.layoutColumns()
+	labels := k.headerLabels()
+	drawn := make([]string, len(k.renderedCols))
+	for i, col := range k.renderedCols {
+		drawn[i] = fitCell(labels[col], k.columnWidth(col))
+	}
+	k.kTb = k.kTb.Headers(drawn...)
+}
+
+// formatRows keeps the drawn columns of the given rows, truncated and padded to the sampled
+// column widths.
+func (k *TableRenderer) formatRows(rows [][]string) [][]string {
+	formatted := make([][]string, len(rows))
+	for i, row := range rows {
+		formatted[i] = make([]string, len(k.renderedCols))
+		for j, col := range k.renderedCols {
+			formatted[i][j] = fitCell(cellAt(row, col), k.columnWidth(col))
+		}
+	}
+	return formatted
+}
+
+func (k *TableRenderer) columnWidth(col int) int {
+	if col < len(k.colWidths) {
+		return k.colWidths[col]
+	}
+	return maxColumnWidth
+}
+
+// sampleColumnWidths sizes every column from an evenly spaced sample of the source rows, so the
+// layout stays stable between pages without measuring every row.
+func (k *TableRenderer) sampleColumnWidths() {
+	widths := make([]int, len(k.headers))
+	for i, header := range k.headers {
+		// leave room for the sort arrow and its position in the sort stack
+		widths[i] = lipgloss.Width(header) + 3
+	}
+
+	total := len(k.rows)
+	rowAt := func(i int) []string { return k.rows[i] }
+	if k.provider != nil {
+		total = k.provider.Count()
+		rowAt = func(i int) []string {
+			if rows := k.provider.RowsAt(i, 1); len(rows) > 0 {
+				return rows[0]
+			}
+			return nil
+		}
+	}
+	step := max(1, total/widthSampleSize)
+	for i := 0; i < total; i += step {
+		for j, cell := range rowAt(i) {
+			if j < len(widths) {
+				widths[j] = max(widths[j], lipgloss.Width(cell))
+			}
+		}
+	}
+	for i := range widths {
+		widths[i] = min(widths[i], maxColumnWidth)
+	}
+	k.colWidths = widths
+}
+
+// fitCell cuts the cell to width, marking the cut with an ellipsis, and pads it with spaces.
+func fitCell(cell string, width int) string {
+	kept, cut := cutCell(cell, width)
+	return kept + cellPadding(kept, cut, width)
+}
+
+// cutCell returns the part of the cell shown in width, leaving room for the ellipsis, and whether
+// the cell was cut.
+func cutCell(cell string, width int) (string, bool) {
+	cell = strings.ReplaceAll(cell, "\n", " ")
+	if lipgloss.Width(cell) <= width {
+		return cell, false
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range cell {
+		rw := lipgloss.Width(string(r))
+		if used+rw > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += rw
+	}
+	return b.String(), true
+}
+
+// cellPadding returns what follows the kept text of a cell: the ellipsis of a cut, then the spaces
+// filling width.
+func cellPadding(kept string, cut bool, width int) string {
+	used := lipgloss.Width(kept)
+	if !cut {
+		return strings.Repeat(" ", max(0, width-used))
+	}
+	return "…" + strings.Repeat(" ", max(0, width-used-1))
+}
+
+// LineRowProvider serves the rows of a delimited text file. It only keeps the offset of every line
+// in memory and reads the requested lines from disk on demand. The first line holds the headers.
+type LineRowProvider struct {
+	mu      sync.Mutex
+	file    *os.File
+	split   func(line string) []string
+	headers []string
+	offsets []int64
+}
+
+// NewLineRowProvider indexes the file at path. split turns a line into cells; nil splits on tabs.
+func NewLineRowProvider(path string, split func(line string) []string) (*LineRowProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		split = func(line string) []string { return strings.Split(line, "\t") }
+	}
+	p := &LineRowProvider{file: file, split: split}
+
+	reader := bufio.NewReader(file)
+	var offset int64
+	for {
+		line, readErr := reader.ReadString('\n')
+		if len(line) > 0 {
+			if p.headers == nil {
+				p.headers = split(strings.TrimRight(line, "\r\n"))
+			} else if strings.TrimSpace(line) != "" {
+				p.offsets = append(p.offsets, offset)
+			}
+			offset += int64(len(line))
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			_ = file.Close()
+			return nil, readErr
+		}
+	}
+	return p, nil
+}
+
+func (p *LineRowProvider) GetHeaders() []string { return p.headers }
+func (p *LineRowProvider) Count() int           { return len(p.offsets) }
+
+func (p *LineRowProvider) RowsAt(offset, limit int) [][]string {
+	if offset < 0 || offset >= len(p.offsets) || limit <= 0 {
+		return nil
+	}
+	end := min(offset+limit, len(p.offsets))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.file.Seek(p.offsets[offset], io.SeekStart); err != nil {
+		logz.Error("Error reading table rows: "+err.Error(), map[string]interface{}{
+			"context": "LineRowProvider",
+			"offset":  offset,
+		})
+		return nil
+	}
+	rows := make([][]string, 0, end-offset)
+	reader := bufio.NewReader(p.file)
+	for len(rows) < end-offset {
+		line, err := reader.ReadString('\n')
+		if strings.TrimSpace(line) != "" {
+			rows = append(rows, p.split(strings.TrimRight(line, "\r\n")))
+		}
+		if err != nil {
+			break
+		}
+	}
+	return rows
+}
+
+// Close releases the underlying file.
+func (p *LineRowProvider) Close() error { return p.file.Close() }
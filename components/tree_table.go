@@ -0,0 +1,669 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/faelmori/logz"
+	"github.com/faelmori/xtui/components/export"
+	. "github.com/faelmori/xtui/types"
+)
+
+// treePathColumn is the header of the column holding the path of every node in flat exports.
+const treePathColumn = "Path"
+
+// TreeNode is a row of a tree table, with one cell per column of the table.
+type TreeNode struct {
+	Cells    []string
+	Children []*TreeNode
+	// Lazy marks a node whose children are read by the loader of the table when it is first
+	// expanded. Children set on a lazy node are replaced by the loaded ones.
+	Lazy bool
+	// Expanded shows the children of the node.
+	Expanded bool
+
+	parent  *TreeNode
+	loaded  bool
+	loading bool
+	loadErr error
+}
+
+// NewTreeNode creates a node with the given cells.
+func NewTreeNode(cells ...string) *TreeNode {
+	return &TreeNode{Cells: cells}
+}
+
+// Add appends children to the node and returns the node, so trees can be built inline.
+func (n *TreeNode) Add(children ...*TreeNode) *TreeNode {
+	for _, child := range children {
+		child.parent = n
+	}
+	n.Children = append(n.Children, children...)
+	return n
+}
+
+// Parent returns the parent of the node, or nil for a root node.
+func (n *TreeNode) Parent() *TreeNode {
+	return n.parent
+}
+
+// Depth returns the number of ancestors of the node.
+func (n *TreeNode) Depth() int {
+	depth := 0
+	for p := n.parent; p != nil; p = p.parent {
+		depth++
+	}
+	return depth
+}
+
+// hasChildren reports whether the node has children, or may have some once loaded.
+func (n *TreeNode) hasChildren() bool {
+	return len(n.Children) > 0 || (n.Lazy && !n.loaded)
+}
+
+// setChildren replaces the children of the node, linking them to it.
+func (n *TreeNode) setChildren(children []*TreeNode) {
+	n.Children = nil
+	n.Add(children...)
+}
+
+// TreeChildLoader returns the children of a lazy node, for instance by running a command.
+type TreeChildLoader func(node *TreeNode) ([]*TreeNode, error)
+
+// TreeOptions holds the settings of a tree table.
+type TreeOptions struct {
+	Title       string
+	ColumnTypes map[string]ColumnType
+	// LoadChildren reads the children of lazy nodes. It runs in the background when a node is
+	// expanded from the keyboard.
+	LoadChildren TreeChildLoader
+}
+
+// treeChildrenMsg carries the children loaded for a lazy node.
+type treeChildrenMsg struct {
+	node     *TreeNode
+	children []*TreeNode
+	err      error
+}
+
+// treeRow is a node shown in the tree table, at the given depth.
+type treeRow struct {
+	node  *TreeNode
+	depth int
+}
+
+// TreeTable is an expandable tree grid: every node is a row, drawn under its parent and indented
+// by its depth. Children can be loaded on demand, siblings are sorted by a column, and the filter
+// keeps the ancestors of the matching nodes in view.
+type TreeTable struct {
+	title       string
+	headers     []string
+	roots       []*TreeNode
+	load        TreeChildLoader
+	columnTypes map[string]ColumnType
+
+	sortCol    int
+	sortAsc    bool
+	sorted     bool
+	sortCursor int
+
+	filter     string
+	filterExpr *FilterExpr
+	filterErr  error
+	matched    map[*TreeNode]bool
+	kept       map[*TreeNode]bool
+
+	rows     []treeRow
+	cursor   int
+	page     int
+	pageSize int
+
+	kTb           *table.Table
+	width, height int
+	showHelp      bool
+	exportDialog  *exportDialog
+	tableNotice
+}
+
+// NewTreeTable creates a tree table showing the root nodes under the given headers.
+func NewTreeTable(headers []string, roots []*TreeNode, opts TreeOptions) *TreeTable {
+	t := &TreeTable{
+		title:       opts.Title,
+		headers:     headers,
+		roots:       roots,
+		load:        opts.LoadChildren,
+		columnTypes: make(map[string]ColumnType),
+		sortAsc:     true,
+		pageSize:    20,
+	}
+	for header, typ := range opts.ColumnTypes {
+		t.columnTypes[header] = typ
+	}
+	for _, root := range roots {
+		root.parent = nil
+		linkChildren(root)
+	}
+	t.kTb = table.New().
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("238"))).
+		StyleFunc(t.cellStyle).
+		Border(lipgloss.ThickBorder())
+	t.rebuild()
+	return t
+}
+
+// linkChildren sets the parent of the children of n, and of their children.
+func linkChildren(n *TreeNode) {
+	for _, child := range n.Children {
+		child.parent = n
+		linkChildren(child)
+	}
+}
+
+// StartTreeTable runs the tree table screen.
+func StartTreeTable(headers []string, roots []*TreeNode, opts TreeOptions) error {
+	t := NewTreeTable(headers, roots, opts)
+
+	p := tea.NewProgram(t, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		logz.Error("Error running tree table screen: "+err.Error(), map[string]interface{}{
+			"context": "StartTreeTable",
+			"title":   opts.Title,
+		})
+		return err
+	}
+	return nil
+}
+
+// Roots returns the root nodes of the tree.
+func (t *TreeTable) Roots() []*TreeNode {
+	return t.roots
+}
+
+// Selected returns the node under the cursor, or nil when no row is shown.
+func (t *TreeTable) Selected() *TreeNode {
+	if t.cursor < 0 || t.cursor >= len(t.rows) {
+		return nil
+	}
+	return t.rows[t.cursor].node
+}
+
+// VisibleNodes returns the nodes shown, in display order.
+func (t *TreeTable) VisibleNodes() []*TreeNode {
+	nodes := make([]*TreeNode, len(t.rows))
+	for i, row := range t.rows {
+		nodes[i] = row.node
+	}
+	return nodes
+}
+
+// Expand shows the children of the node, loading those of a lazy node first.
+func (t *TreeTable) Expand(n *TreeNode) error {
+	if n.Lazy && !n.loaded && t.load != nil {
+		children, err := t.load(n)
+		t.loaded(n, children, err)
+		if err != nil {
+			return err
+		}
+	}
+	n.Expanded = true
+	t.rebuild()
+	return nil
+}
+
+// Collapse hides the children of the node.
+func (t *TreeTable) Collapse(n *TreeNode) {
+	n.Expanded = false
+	t.rebuild()
+}
+
+// expandCmd expands the node, loading the children of a lazy node in the background.
+func (t *TreeTable) expandCmd(n *TreeNode) tea.Cmd {
+	if !n.Lazy || n.loaded || t.load == nil {
+		if n.hasChildren() {
+			n.Expanded = true
+			t.rebuild()
+		}
+		return nil
+	}
+	if n.loading {
+		return nil
+	}
+	n.loading = true
+	n.Expanded = true
+	load := t.load
+	return func() tea.Msg {
+		children, err := load(n)
+		return treeChildrenMsg{node: n, children: children, err: err}
+	}
+}
+
+// loaded stores the children loaded for a lazy node. On error the node is kept unloaded, so it
+// can be expanded again.
+func (t *TreeTable) loaded(n *TreeNode, children []*TreeNode, err error) {
+	n.loading = false
+	n.loadErr = err
+	if err != nil {
+		n.Expanded = false
+		return
+	}
+	n.loaded = true
+	n.setChildren(children)
+	for _, child := range children {
+		linkChildren(child)
+	}
+	if t.filterActive() {
+		t.matchTree()
+	}
+}
+
+// SortBy sorts the siblings of every level by the column, ascending or descending.
+func (t *TreeTable) SortBy(header string, asc bool) {
+	col := slices.Index(t.headers, header)
+	if col < 0 {
+		return
+	}
+	t.sortCol, t.sortAsc, t.sorted = col, asc, true
+	t.sortCursor = col
+	t.rebuild()
+}
+
+// sortCursorColumn sorts by the column under the sort cursor; again on the same column reverses it.
+func (t *TreeTable) sortCursorColumn() {
+	asc := true
+	if t.sorted && t.sortCol == t.sortCursor {
+		asc = !t.sortAsc
+	}
+	if t.sortCursor < len(t.headers) {
+		t.SortBy(t.headers[t.sortCursor], asc)
+	}
+}
+
+// siblings returns the nodes in display order.
+func (t *TreeTable) siblings(nodes []*TreeNode) []*TreeNode {
+	if !t.sorted {
+		return nodes
+	}
+	typ := t.columnType(t.sortCol)
+	sorted := slices.Clone(nodes)
+	slices.SortStableFunc(sorted, func(a, b *TreeNode) int {
+		c := CompareCells(typ, cellAt(a.Cells, t.sortCol), cellAt(b.Cells, t.sortCol))
+		if !t.sortAsc {
+			return -c
+		}
+		return c
+	})
+	return sorted
+}
+
+func (t *TreeTable) columnType(col int) ColumnType {
+	if col < 0 || col >= len(t.headers) {
+		return ColumnString
+	}
+	if typ, ok := t.columnTypes[t.headers[col]]; ok {
+		return typ
+	}
+	return ColumnString
+}
+
+// SetFilter filters the nodes with an expression in the filter bar syntax. Matching nodes are shown
+// with their ancestors, which are expanded to reveal them; only loaded nodes are searched.
+func (t *TreeTable) SetFilter(filter string) error {
+	t.filter = filter
+	t.applyFilter()
+	return t.filterErr
+}
+
+func (t *TreeTable) applyFilter() {
+	expr, err := ParseFilter(t.filter, t.headers)
+	t.filterErr = err
+	if err != nil {
+		return
+	}
+	t.filterExpr = expr
+	t.cursor, t.page = 0, 0
+	t.matchTree()
+	t.rebuild()
+}
+
+func (t *TreeTable) filterActive() bool {
+	return t.filterExpr != nil && len(t.filterExpr.Terms) > 0
+}
+
+// matchTree finds the nodes matching the filter and those with a matching descendant, which are
+// expanded so the matches are in view.
+func (t *TreeTable) matchTree() {
+	t.matched = make(map[*TreeNode]bool)
+	t.kept = make(map[*TreeNode]bool)
+	var match func(n *TreeNode) bool
+	match = func(n *TreeNode) bool {
+		kept := t.filterExpr.Match(n.Cells, t.columnType)
+		t.matched[n] = kept
+		for _, child := range n.Children {
+			if match(child) {
+				kept = true
+				n.Expanded = true
+			}
+		}
+		t.kept[n] = kept
+		return kept
+	}
+	for _, root := range t.roots {
+		match(root)
+	}
+}
+
+// rebuild lists the rows shown, from the expanded nodes that pass the filter, and loads the rows
+// of the current page into the lipgloss table.
+func (t *TreeTable) rebuild() {
+	selected := t.Selected()
+	t.rows = t.rows[:0]
+	t.walk(t.roots, 0, false)
+	if i := slices.IndexFunc(t.rows, func(r treeRow) bool { return r.node == selected }); i >= 0 {
+		t.cursor = i
+	}
+	t.cursor = max(0, min(t.cursor, len(t.rows)-1))
+	t.page = t.cursor / t.pageSize
+	t.syncTable()
+}
+
+// walk adds the nodes to the rows shown. Nodes that fail the filter are left out unless an
+// ancestor matched it.
+func (t *TreeTable) walk(nodes []*TreeNode, depth int, underMatch bool) {
+	for _, n := range t.siblings(nodes) {
+		if t.filterActive() && !t.kept[n] && !underMatch {
+			continue
+		}
+		t.rows = append(t.rows, treeRow{node: n, depth: depth})
+		if n.Expanded {
+			t.walk(n.Children, depth+1, underMatch || (t.filterActive() && t.matched[n]))
+		}
+	}
+}
+
+// treeCell returns the text of a cell, the first column being indented and marked with the state
+// of the node.
+func treeCell(row treeRow, col int) string {
+	cell := cellAt(row.node.Cells, col)
+	if col != 0 {
+		return cell
+	}
+	marker := "  "
+	switch {
+	case row.node.loading:
+		marker = "… "
+	case row.node.loadErr != nil:
+		marker = "! "
+	case row.node.hasChildren() && row.node.Expanded:
+		marker = "▾ "
+	case row.node.hasChildren():
+		marker = "▸ "
+	}
+	return strings.Repeat("  ", row.depth) + marker + cell
+}
+
+// syncTable loads the headers and the rows of the current page into the lipgloss table, fitted
+// to the widest cell of each column among the rows shown.
+func (t *TreeTable) syncTable() {
+	widths := make([]int, len(t.headers))
+	labels := make([]string, len(t.headers))
+	for col, header := range t.headers {
+		labels[col] = header
+		if t.sorted && col == t.sortCol && t.sortAsc {
+			labels[col] += " ▲"
+		} else if t.sorted && col == t.sortCol {
+			labels[col] += " ▼"
+		}
+		widths[col] = lipgloss.Width(labels[col])
+	}
+	for _, row := range t.rows {
+		for col := range t.headers {
+			widths[col] = min(max(widths[col], lipgloss.Width(treeCell(row, col))), maxColumnWidth)
+		}
+	}
+
+	headers := make([]string, len(t.headers))
+	for col := range t.headers {
+		headers[col] = fitCell(labels[col], widths[col])
+	}
+	start := t.page * t.pageSize
+	end := min(start+t.pageSize, len(t.rows))
+	rows := make([][]string, 0, max(0, end-start))
+	for _, row := range t.rows[min(start, end):end] {
+		cells := make([]string, len(t.headers))
+		for col := range t.headers {
+			cells[col] = fitCell(treeCell(row, col), widths[col])
+		}
+		rows = append(rows, cells)
+	}
+	t.kTb.ClearRows()
+	t.kTb = t.kTb.Headers(headers...).Rows(rows...)
+}
+
+// cellStyle is the StyleFunc of the lipgloss table.
+func (t *TreeTable) cellStyle(row, col int) lipgloss.Style {
+	switch {
+	case row == table.HeaderRow && col == t.sortCursor:
+		return headerCursorStyle.Padding(0, 1)
+	case row == table.HeaderRow:
+		return tableHeaderStyle.Padding(0, 1)
+	case t.page*t.pageSize+row == t.cursor:
+		return selectedRowStyle.Padding(0, 1)
+	}
+	if i := t.page*t.pageSize + row; i < len(t.rows) && t.filterActive() && !t.matched[t.rows[i].node] {
+		return tableBaseStyle.Faint(true).Padding(0, 1)
+	}
+	return tableBaseStyle.Padding(0, 1)
+}
+
+// moveCursor moves the cursor by delta rows, keeping it on the rows shown.
+func (t *TreeTable) moveCursor(delta int) {
+	t.cursor = max(0, min(t.cursor+delta, len(t.rows)-1))
+	t.page = t.cursor / t.pageSize
+	t.syncTable()
+}
+
+// moveTo moves the cursor to the node, when it is shown.
+func (t *TreeTable) moveTo(n *TreeNode) {
+	if i := slices.IndexFunc(t.rows, func(r treeRow) bool { return r.node == n }); i >= 0 {
+		t.moveCursor(i - t.cursor)
+	}
+}
+
+func (t *TreeTable) Init() tea.Cmd {
+	return nil
+}
+
+func (t *TreeTable) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch message := msg.(type) {
+	case treeChildrenMsg:
+		t.loaded(message.node, message.children, message.err)
+		t.rebuild()
+		if message.err != nil {
+			return t, t.notify(Error, "Loading children failed: "+message.err.Error())
+		}
+	case tableNoticeMsg:
+		if message.id == t.noticeID {
+			t.notice = nil
+		}
+	case tea.WindowSizeMsg:
+		t.width, t.height = message.Width, message.Height
+		t.pageSize = max(1, t.height-tableChromeLines)
+		t.rebuild()
+	case tea.KeyMsg:
+		if t.exportDialog != nil {
+			submit, cancel, cmd := t.exportDialog.update(message)
+			switch {
+			case submit:
+				return t, t.runExportDialog()
+			case cancel:
+				t.exportDialog = nil
+			}
+			return t, cmd
+		}
+		node := t.Selected()
+		switch message.String() {
+		case "ctrl+c":
+			return t, tea.Quit
+		case "q":
+			// q quits unless it is being typed into the filter
+			if t.filter == "" {
+				return t, tea.Quit
+			}
+			t.typeFilter(message)
+		case "down":
+			t.moveCursor(1)
+		case "up":
+			t.moveCursor(-1)
+		case "pgdown":
+			t.moveCursor(t.pageSize)
+		case "pgup":
+			t.moveCursor(-t.pageSize)
+		case "right":
+			if node != nil && node.Expanded && len(node.Children) > 0 {
+				t.moveCursor(1)
+			} else if node != nil {
+				return t, t.expandCmd(node)
+			}
+		case "left":
+			if node != nil && node.Expanded {
+				t.Collapse(node)
+			} else if node != nil && node.parent != nil {
+				t.moveTo(node.parent)
+			}
+		case " ":
+			if node != nil && node.Expanded {
+				t.Collapse(node)
+			} else if node != nil {
+				return t, t.expandCmd(node)
+			}
+		case "enter":
+			t.applyFilter()
+		case "esc":
+			t.filter = ""
+			t.applyFilter()
+		case "backspace":
+			if len(t.filter) > 0 {
+				t.filter = t.filter[:len(t.filter)-1]
+				_, t.filterErr = ParseFilter(t.filter, t.headers)
+			}
+		case "ctrl+o":
+			t.sortCursor = (t.sortCursor + 1) % max(1, len(t.headers))
+			t.syncTable()
+		case "ctrl+s":
+			t.sortCursorColumn()
+		case "ctrl+e":
+			t.exportDialog = newExportDialog("csv", false)
+		case "ctrl+h":
+			t.showHelp = !t.showHelp
+		default:
+			t.typeFilter(message)
+		}
+	}
+	return t, nil
+}
+
+// typeFilter appends the typed characters to the filter; other keys are ignored.
+func (t *TreeTable) typeFilter(msg tea.KeyMsg) {
+	if msg.Type != tea.KeyRunes || msg.Alt {
+		return
+	}
+	t.filter += string(msg.Runes)
+	_, t.filterErr = ParseFilter(t.filter, t.headers)
+}
+
+func (t *TreeTable) View() string {
+	if t.exportDialog != nil {
+		return overlayBox(t.exportDialog.view("Export tree"), t.width, t.height)
+	}
+
+	filterBar := t.filter
+	if t.filterErr != nil {
+		filterBar += "\n" + errorStyle.Render(t.filterErr.Error())
+	}
+	status := fmt.Sprintf("Rows: %d  Page: %d/%d", len(t.rows), t.page+1, max(1, (len(t.rows)+t.pageSize-1)/t.pageSize))
+	if node := t.Selected(); node != nil && node.loadErr != nil {
+		status += "  " + errorStyle.Render(node.loadErr.Error())
+	}
+
+	helpText := ""
+	if t.showHelp {
+		helpText = "\nAtalhos:\n" +
+			"  - q, ctrl+c: Sair (q só com o filtro vazio)\n" +
+			"  - up/down, pgup/pgdown: Mover a seleção\n" +
+			"  - right: Expandir o nó (carrega os filhos) ou ir ao primeiro filho\n" +
+			"  - left: Recolher o nó ou ir ao nó pai\n" +
+			"  - space: Expandir/recolher o nó\n" +
+			"  - filtro: Name~^lib Size>1G (enter aplica, esc limpa; os ancestrais continuam visíveis)\n" +
+			"  - ctrl+o: Selecionar a próxima coluna para ordenação\n" +
+			"  - ctrl+s: Ordenar os irmãos pela coluna selecionada (de novo inverte a direção)\n" +
+			"  - ctrl+e: Exportar a árvore\n"
+	}
+	toggleHelpText := "\nPressione ctrl+h para exibir/ocultar os atalhos."
+	if t.notice != nil {
+		toggleHelpText = "\n" + t.notice.Render() + toggleHelpText
+	}
+	return fmt.Sprintf("\nFilter: %s\n\n%s\n%s\n%s%s", filterBar, t.kTb.String(), status, helpText, toggleHelpText)
+}
+
+// ExportRows returns the nodes selected by the scope as flat rows, each starting with the path of
+// the node: the first cells of its ancestors and its own, joined with "/". ExportFiltered exports
+// the rows shown, ExportAll every loaded node and ExportSelected the node under the cursor with its
+// loaded descendants.
+func (t *TreeTable) ExportRows(scope ExportScope) TableDataHandler {
+	var nodes []*TreeNode
+	var collect func(n *TreeNode)
+	collect = func(n *TreeNode) {
+		nodes = append(nodes, n)
+		for _, child := range t.siblings(n.Children) {
+			collect(child)
+		}
+	}
+	switch scope {
+	case ExportAll:
+		for _, root := range t.siblings(t.roots) {
+			collect(root)
+		}
+	case ExportSelected:
+		if node := t.Selected(); node != nil {
+			collect(node)
+		}
+	default:
+		nodes = t.VisibleNodes()
+	}
+
+	rows := make([][]string, len(nodes))
+	for i, n := range nodes {
+		rows[i] = append([]string{treePath(n)}, n.Cells...)
+	}
+	data := export.NewTable(append([]string{treePathColumn}, t.headers...), rows)
+	data.Title = t.title
+	data.Types = t.columnTypes
+	return data
+}
+
+// treePath joins the first cells of the ancestors of the node and its own.
+func treePath(n *TreeNode) string {
+	var parts []string
+	for ; n != nil; n = n.parent {
+		parts = append(parts, cellAt(n.Cells, 0))
+	}
+	slices.Reverse(parts)
+	return strings.Join(parts, "/")
+}
+
+// Export writes the rows shown to filename with a registered format. An empty format is guessed
+// from the file extension.
+func (t *TreeTable) Export(format, filename string) error {
+	return export.ExportToFile(format, filename, t.ExportRows(ExportFiltered))
+}
+
+// runExportDialog writes the export chosen in the dialog, closes it and reports the outcome.
+func (t *TreeTable) runExportDialog() tea.Cmd {
+	d := t.exportDialog
+	t.exportDialog = nil
+	return t.runExport(d, func(d *exportDialog) TableDataHandler { return t.ExportRows(d.scope) })
+}
@@ -186,10 +186,25 @@ func getInstalledAppsHandler(name string, status string, method string) (*AppsTa
 	return &AppsTableHandler{apps: apps}, nil
 }
 
+// installedAppsTableID identifica a tabela de aplicativos instalados nas visões salvas.
+const installedAppsTableID = "pkg-list"
+
 // ShowInstalledAppsTable exibe a tabela de aplicativos instalados.
 // Recebe uma lista de argumentos.
 // Retorna um erro, se houver.
 func ShowInstalledAppsTable(args ...string) error {
+	return ShowInstalledAppsTableView("", args...)
+}
+
+// ShowInstalledAppsTableView exibe a tabela de aplicativos instalados com uma visão salva.
+// Recebe o nome da visão (vazio abre a visão "default", se existir) e uma lista de argumentos.
+// Retorna um erro, se houver.
+func ShowInstalledAppsTableView(view string, args ...string) error {
+	if view != "" {
+		if _, err := cmp.FindTableView(installedAppsTableID, view); err != nil {
+			return err
+		}
+	}
 	name := ""
 	if len(args) > 0 {
 		name = args[0]
@@ -211,26 +226,53 @@ func ShowInstalledAppsTable(args ...string) error {
 		"row":    lipgloss.Color("#252"),
 	}
 
-	var fields []t.Field
-	for _, header := range handler.GetHeaders() {
-		fields = append(fields, &t.InputField{
-			Ph:  header,
-			Tp:  "text",
-			Val: "",
-			Req: false,
-			Min: 0,
-			Max: 0,
-			Err: "",
-			Vld: nil,
-		})
-	}
-	return cmp.StartTableScreen(
-		t.FormConfig{
-			Title:  "Installed Apps",
-			Fields: fields,
+	return cmp.StartTableScreenWithData(handler, cmp.TableOptions{
+		Title:        "Installed Apps",
+		ID:           installedAppsTableID,
+		View:         view,
+		CustomStyles: customStyles,
+		ColumnTypes:  map[string]t.ColumnType{"Version": t.ColumnSemver},
+		Actions:      appsTableActions(handler, name, status, method),
+		DetailPane:   cmp.DetailBottom,
+		Aggregations: map[string]cmp.Aggregation{"Name": cmp.AggregateCount},
+		StyleRules: []cmp.StyleRule{
+			{When: "Status=residual", Column: "Status", Style: cmp.CellStyle{Foreground: "#FF7698", Bold: true}},
+			{When: "Method=manual", Column: "Method", Style: cmp.CellStyle{Foreground: "#75FBAB"}},
 		},
-		customStyles,
-	)
+	})
+}
+
+// appsTableActions retorna as ações em lote da tabela de aplicativos instalados.
+// As ações executam o apt-get com sudo nos pacotes marcados e recarregam o handler em seguida.
+func appsTableActions(handler *AppsTableHandler, name string, status string, method string) []cmp.TableAction {
+	aptAction := func(args ...string) func(rows [][]string) error {
+		return func(rows [][]string) error {
+			cmdArgs := append([]string{"apt-get"}, args...)
+			for _, row := range rows {
+				if len(row) > 0 && row[0] != "" {
+					cmdArgs = append(cmdArgs, row[0])
+				}
+			}
+			cmd := exec.Command("sudo", cmdArgs...) //nolint:gosec
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			cmd.Stdin = os.Stdin // Permite que o sudo peça a senha
+			if err := cmd.Run(); err != nil {
+				logz.Error("Error running apt-get: "+err.Error(), nil)
+				return err
+			}
+			refreshed, err := getInstalledAppsHandler(name, status, method)
+			if err != nil {
+				return err
+			}
+			handler.apps = refreshed.apps
+			return nil
+		}
+	}
+	return []cmp.TableAction{
+		{Name: "Remove selected packages", Run: aptAction("remove", "-y"), Terminal: true, Confirm: true},
+		{Name: "Reinstall selected packages", Run: aptAction("install", "--reinstall", "-y"), Terminal: true, Confirm: true},
+	}
 }
 
 // installGoogleAuthenticator instala o Google Authenticator.
@@ -0,0 +1,239 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Cell value parsers and comparators for the table column types.
+
+var (
+	byteUnits = map[string]float64{
+		"":  1,
+		"K": 1 << 10,
+		"M": 1 << 20,
+		"G": 1 << 30,
+		"T": 1 << 40,
+		"P": 1 << 50,
+	}
+	timeLayouts = []string{
+		time.RFC3339Nano,
+		time.RFC3339,
+		time.DateTime,
+		time.DateOnly,
+		time.TimeOnly,
+		time.RFC1123Z,
+		time.RFC1123,
+		time.RFC850,
+		time.ANSIC,
+		time.UnixDate,
+		time.Stamp,
+		"02/01/2006 15:04:05",
+		"02/01/2006",
+	}
+	// thousandsNumber matches numbers whose commas group the digits by thousands, like "1,234.5".
+	thousandsNumber = regexp.MustCompile(`^[+-]?\d{1,3}(,\d{3})+(\.\d*)?$`)
+)
+
+// CompareCells compares two cell values using the comparator that matches the column type. Values
+// that cannot be parsed as the column type are ordered after the valid ones and compared as text.
+func CompareCells(typ ColumnType, a, b string) int {
+	var av, bv float64
+	var aErr, bErr error
+	switch typ {
+	case ColumnInt, ColumnFloat:
+		av, aErr = ParseNumber(a)
+		bv, bErr = ParseNumber(b)
+	case ColumnBytes:
+		av, aErr = ParseByteSize(a)
+		bv, bErr = ParseByteSize(b)
+	case ColumnTime:
+		at, atErr := ParseTime(a)
+		bt, btErr := ParseTime(b)
+		if atErr == nil && btErr == nil {
+			return at.Compare(bt)
+		}
+		aErr, bErr = atErr, btErr
+	case ColumnSemver:
+		return CompareVersions(a, b)
+	default:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+	switch {
+	case aErr == nil && bErr == nil:
+		if av < bv {
+			return -1
+		}
+		if av > bv {
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// ParseNumber parses a number, ignoring surrounding spaces and thousands separators. A single comma
+// that does not group thousands is a decimal comma, so "1,234" is 1234 and "1,5" is 1.5.
+func ParseNumber(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case thousandsNumber.MatchString(s):
+		s = strings.ReplaceAll(s, ",", "")
+	case strings.Count(s, ",") == 1 && !strings.Contains(s, "."):
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return v, nil
+}
+
+// ParseByteSize parses human readable sizes such as "512", "1.2G", "3 MB" or "1.5GiB" into bytes.
+// Units are binary multiples, as printed by du, ls -h and similar tools.
+func ParseByteSize(s string) (float64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimSuffix(s, "B")
+	s = strings.TrimSuffix(s, "I")
+	unit := ""
+	if n := len(s); n > 0 && unicode.IsLetter(rune(s[n-1])) {
+		unit = s[n-1:]
+		s = strings.TrimSpace(s[:n-1])
+	}
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size unit %q", unit)
+	}
+	v, err := ParseNumber(s)
+	if err != nil {
+		return 0, err
+	}
+	return v * multiplier, nil
+}
+
+// FormatByteSize formats a number of bytes with the largest binary unit that keeps it at or above
+// one, rounded to one decimal, such as "512B" or "1.5GB", as ParseByteSize reads them back.
+func FormatByteSize(v float64) string {
+	units := []string{"", "K", "M", "G", "T", "P"}
+	i := 0
+	for i < len(units)-1 && math.Abs(v) >= byteUnits[units[i+1]] {
+		i++
+	}
+	return strconv.FormatFloat(math.Round(v/byteUnits[units[i]]*10)/10, 'f', -1, 64) + units[i] + "B"
+}
+
+// ParseTime parses a cell value with the first matching layout of the common date/time formats.
+func ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// CompareVersions compares version strings piece by piece. Numeric pieces are compared as numbers
+// and the others as text, so "1.10" sorts after "1.9" and Debian versions like "1:2.3-1ubuntu2"
+// also order as expected. A Debian epoch, the number before a colon, is compared first, and a
+// version without one has epoch 0, so "1:2.3" sorts after "2.0". A pre-release, a text piece after
+// a dash or anything after a tilde, sorts before its release: "1.0-rc1" and "1.0~beta" before
+// "1.0".
+func CompareVersions(a, b string) int {
+	ae, a := versionEpoch(a)
+	be, b := versionEpoch(b)
+	if ae != be {
+		if ae < be {
+			return -1
+		}
+		return 1
+	}
+	ap, bp := versionPieces(a), versionPieces(b)
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		an, aErr := strconv.ParseUint(ap[i].text, 10, 64)
+		bn, bErr := strconv.ParseUint(bp[i].text, 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return 1
+		case bErr == nil:
+			return -1
+		default:
+			if c := strings.Compare(ap[i].text, bp[i].text); c != 0 {
+				return c
+			}
+		}
+	}
+	switch {
+	case len(ap) > len(bp) && ap[len(bp)].pre:
+		return -1
+	case len(bp) > len(ap) && bp[len(ap)].pre:
+		return 1
+	}
+	return len(ap) - len(bp)
+}
+
+// versionEpoch splits the Debian epoch, the digits before a colon, off a version. A version without
+// one has epoch 0.
+func versionEpoch(v string) (uint64, string) {
+	v = strings.TrimSpace(v)
+	if before, after, ok := strings.Cut(v, ":"); ok {
+		if epoch, err := strconv.ParseUint(before, 10, 64); err == nil {
+			return epoch, after
+		}
+	}
+	return 0, v
+}
+
+// versionPiece is a run of digits or letters of a version. pre marks the first piece of a
+// pre-release suffix.
+type versionPiece struct {
+	text string
+	pre  bool
+}
+
+func versionPieces(v string) []versionPiece {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	var pieces []versionPiece
+	var current strings.Builder
+	digits := false
+	var sep rune
+	flush := func() {
+		if current.Len() > 0 {
+			pre := sep == '~' || sep == '-' && !digits
+			pieces = append(pieces, versionPiece{text: current.String(), pre: pre})
+			current.Reset()
+			sep = 0
+		}
+	}
+	for _, r := range v {
+		isDigit := unicode.IsDigit(r)
+		if !isDigit && !unicode.IsLetter(r) {
+			flush()
+			sep = r
+			continue
+		}
+		if current.Len() > 0 && isDigit != digits {
+			flush()
+		}
+		digits = isDigit
+		current.WriteRune(r)
+	}
+	flush()
+	return pieces
+}
@@ -0,0 +1,131 @@
+package types
+
+import "testing"
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{in: "42", want: 42},
+		{in: " -3.5 ", want: -3.5},
+		{in: "1,234", want: 1234},
+		{in: "1,234,567.25", want: 1234567.25},
+		{in: "1,5", want: 1.5},
+		{in: "12,34", want: 12.34},
+		{in: "1,2,3", wantErr: true},
+		{in: "NaN", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseNumber(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseNumber(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseNumber(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{in: "512", want: 512},
+		{in: "512B", want: 512},
+		{in: "1K", want: 1024},
+		{in: "1.5GiB", want: 1.5 * 1024 * 1024 * 1024},
+		{in: "3 MB", want: 3 * 1024 * 1024},
+		{in: "2t", want: 2 * 1024 * 1024 * 1024 * 1024},
+		{in: "1X", wantErr: true},
+		{in: "MB", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseByteSize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseByteSize(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	for _, in := range []string{"512B", "1KB", "1.5GB", "3MB"} {
+		size, err := ParseByteSize(in)
+		if err != nil {
+			t.Fatalf("ParseByteSize(%q): %v", in, err)
+		}
+		if got := FormatByteSize(size); got != in {
+			t.Errorf("FormatByteSize(ParseByteSize(%q)) = %q", in, got)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.9", b: "1.10", want: -1},
+		{a: "1.10", b: "1.9", want: 1},
+		{a: "v1.2.3", b: "1.2.3", want: 0},
+		{a: "1.0", b: "1.0.1", want: -1},
+		{a: "1.0-rc1", b: "1.0", want: -1},
+		{a: "1.0~beta", b: "1.0", want: -1},
+		{a: "1.0~beta", b: "1.0-rc1", want: -1},
+		{a: "1.0-1", b: "1.0", want: 1},
+		{a: "2.3-1ubuntu2", b: "2.3-1ubuntu10", want: -1},
+		{a: "1:2.3", b: "2.0", want: 1},
+		{a: "0:2.3", b: "2.3", want: 0},
+		{a: "1:1.0", b: "2:0.1", want: -1},
+		{a: "1.0a", b: "1.0b", want: -1},
+	}
+	for _, tt := range tests {
+		got := CompareVersions(tt.a, tt.b)
+		if sign(got) != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if back := CompareVersions(tt.b, tt.a); sign(back) != -tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.b, tt.a, back, -tt.want)
+		}
+	}
+}
+
+func TestCompareCells(t *testing.T) {
+	tests := []struct {
+		typ  ColumnType
+		a, b string
+		want int
+	}{
+		{typ: ColumnInt, a: "9", b: "10", want: -1},
+		{typ: ColumnInt, a: "1,000", b: "999", want: 1},
+		{typ: ColumnInt, a: "n/a", b: "5", want: 1},
+		{typ: ColumnBytes, a: "1K", b: "900", want: 1},
+		{typ: ColumnTime, a: "2024-01-02", b: "2023-12-31", want: 1},
+		{typ: ColumnString, a: "abc", b: "ABD", want: -1},
+	}
+	for _, tt := range tests {
+		if got := CompareCells(tt.typ, tt.a, tt.b); sign(got) != tt.want {
+			t.Errorf("CompareCells(%s, %q, %q) = %d, want %d", tt.typ, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -1,5 +1,7 @@
 package types
 
+// DataExporter writes table data to files in the common export formats. The implementation lives
+// in the components/export package, see export.NewDataExporter.
 type DataExporter interface {
 	ExportToCSV(filename string) error
 	ExportToYAML(filename string) error
@@ -9,33 +11,3 @@ type DataExporter interface {
 	ExportToPDF(filename string) error
 	ExportToMarkdown(filename string) error
 }
-type dataExporter struct{}
-
-func (e dataExporter) ExportToCSV(filename string) error {
-	// Implementation for exporting to CSV
-	return nil
-}
-func (e dataExporter) ExportToYAML(filename string) error {
-	// Implementation for exporting to YAML
-	return nil
-}
-func (e dataExporter) ExportToJSON(filename string) error {
-	// Implementation for exporting to JSON
-	return nil
-}
-func (e dataExporter) ExportToXML(filename string) error {
-	// Implementation for exporting to XML
-	return nil
-}
-func (e dataExporter) ExportToExcel(filename string) error {
-	// Implementation for exporting to Excel
-	return nil
-}
-func (e dataExporter) ExportToPDF(filename string) error {
-	// Implementation for exporting to PDF
-	return nil
-}
-func (e dataExporter) ExportToMarkdown(filename string) error {
-	// Implementation for exporting to Markdown
-	return nil
-}
@@ -1,5 +1,11 @@
 package types
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 // Form and Field Error interface and types
 type FormError interface {
 	Error() string
@@ -25,18 +31,129 @@ func (v *formError) ErrorOrNil() error {
 	return v
 }
 
+// Is matches errors of the same rule, so a formatted error still matches its template:
+// errors.Is(err, ErrInvalidMinLen).
+func (v *formError) Is(target error) bool {
+	t, ok := target.(*formError)
+	return ok && t.Rule == v.Rule
+}
+
+// format returns a copy of the error with the placeholders of its message filled with args.
+func (v *formError) format(args ...interface{}) *formError {
+	return &formError{Rule: v.Rule, Message: fmt.Sprintf(v.Message, args...)}
+}
+
 var (
 	ErrRequired           = &formError{Rule: "Required", Message: "This field is required"}
 	ErrInvalidEmail       = &formError{Rule: "InvalidEmail", Message: "This field must be a valid email address"}
 	ErrInvalidURL         = &formError{Rule: "InvalidURL", Message: "This field must be a valid URL"}
 	ErrInvalidIP          = &formError{Rule: "InvalidIP", Message: "This field must be a valid IP address"}
 	ErrInvalidPort        = &formError{Rule: "InvalidPort", Message: "This field must be a valid Port number"}
-	ErrInvalidMin         = &formError{Rule: "InvalidMin", Message: "This field must be a minimum of %d"}
-	ErrInvalidMax         = &formError{Rule: "InvalidMax", Message: "This field must be a maximum of %d"}
+	ErrInvalidInt         = &formError{Rule: "InvalidInt", Message: "This field must be an integer"}
+	ErrInvalidNumber      = &formError{Rule: "InvalidNumber", Message: "This field must be a number"}
+	ErrInvalidBool        = &formError{Rule: "InvalidBool", Message: "This field must be yes or no"}
+	ErrInvalidDuration    = &formError{Rule: "InvalidDuration", Message: "This field must be a duration such as 1h30m"}
+	ErrInvalidDate        = &formError{Rule: "InvalidDate", Message: "This field must be a date or time such as 2006-01-02 15:04"}
+	ErrInvalidMin         = &formError{Rule: "InvalidMin", Message: "This field must be a minimum of %v"}
+	ErrInvalidMax         = &formError{Rule: "InvalidMax", Message: "This field must be a maximum of %v"}
 	ErrInvalidMinLen      = &formError{Rule: "InvalidMinLen", Message: "This field must be a minimum length of %d"}
 	ErrInvalidMaxLen      = &formError{Rule: "InvalidMaxLen", Message: "This field must be a maximum length of %d"}
+	ErrInvalidMinItems    = &formError{Rule: "InvalidMinItems", Message: "This field must have at least %d items"}
+	ErrInvalidMaxItems    = &formError{Rule: "InvalidMaxItems", Message: "This field must have at most %d items"}
+	ErrInvalidOption      = &formError{Rule: "InvalidOption", Message: "This field must be one of the options, not %q"}
 	ErrInvalidRegexp      = &formError{Rule: "InvalidRegexp", Message: "This field must match the regular expression %s"}
 	ErrInvalidPattern     = &formError{Rule: "InvalidPattern", Message: "This field must match the pattern %s"}
 	ErrInvalidCustom      = &formError{Rule: "InvalidCustom", Message: "This field must match the custom rule"}
 	ErrInvalidCustomCheck = &formError{Rule: "InvalidCustomCheck", Message: "This field must match the custom check"}
+	ErrInvalidRule        = &formError{Rule: "InvalidRule", Message: "The validation rule parameter %q is invalid"}
+	ErrUnknownRule        = &formError{Rule: "UnknownRule", Message: "The validation rule %q is not registered"}
 )
+
+// FieldErrors collects the validation errors of the fields of a form, keyed by field name. It is a
+// FormError whose FieldsError holds every message, in form order.
+type FieldErrors struct {
+	names []string
+	errs  map[string]error
+}
+
+// NewFieldErrors creates an empty collection of field errors.
+func NewFieldErrors() *FieldErrors {
+	return &FieldErrors{errs: make(map[string]error)}
+}
+
+// Set records the error of the named field, or clears it when err is nil.
+func (e *FieldErrors) Set(name string, err error) {
+	if err == nil {
+		if _, ok := e.errs[name]; ok {
+			delete(e.errs, name)
+			e.names = slices.DeleteFunc(e.names, func(n string) bool { return n == name })
+		}
+		return
+	}
+	if _, ok := e.errs[name]; !ok {
+		e.names = append(e.names, name)
+	}
+	e.errs[name] = err
+}
+
+// Get returns the error of the named field, or nil when it is valid.
+func (e *FieldErrors) Get(name string) error { return e.errs[name] }
+
+// Fields returns the names of the invalid fields, in the order their errors were first recorded.
+func (e *FieldErrors) Fields() []string { return append([]string(nil), e.names...) }
+
+// Len returns the number of invalid fields.
+func (e *FieldErrors) Len() int { return len(e.names) }
+
+func (e *FieldErrors) Error() string {
+	messages := make([]string, len(e.names))
+	for i, name := range e.names {
+		messages[i] = name + ": " + e.errs[name].Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ErrorOrNil returns the collection as an error, or nil when no field is invalid.
+func (e *FieldErrors) ErrorOrNil() error {
+	if e == nil || len(e.names) == 0 {
+		return nil
+	}
+	return e
+}
+
+// FieldError returns the message of the first invalid field, keyed by its name.
+func (e *FieldErrors) FieldError() map[string]string {
+	if len(e.names) == 0 {
+		return map[string]string{}
+	}
+	return map[string]string{e.names[0]: e.errs[e.names[0]].Error()}
+}
+
+// FieldsError returns the message of every invalid field, keyed by field name.
+func (e *FieldErrors) FieldsError() map[string]string {
+	fields := make(map[string]string, len(e.names))
+	for _, name := range e.names {
+		fields[name] = e.errs[name].Error()
+	}
+	return fields
+}
+
+// Unwrap returns the errors of the fields, so errors.Is finds the rules that failed.
+func (e *FieldErrors) Unwrap() []error {
+	errs := make([]error, len(e.names))
+	for i, name := range e.names {
+		errs[i] = e.errs[name]
+	}
+	return errs
+}
+
+// ValidateFields validates the value of every field, as returned by value, and collects the errors
+// of all the invalid ones, keyed by FormFieldNames.
+func ValidateFields(fields []FormField, value func(i int) string) *FieldErrors {
+	errs := NewFieldErrors()
+	names := FormFieldNames(fields)
+	for i, field := range fields {
+		errs.Set(names[i], field.Validate(value(i)))
+	}
+	return errs
+}
@@ -13,5 +13,5 @@ type FormConfig struct {
 	FormFields
 }
 
-func (f FormConfig) GetTitle() string                  { return f.Title }
-func (f FormConfig) GetFields() []FormInputObject[any] { return f.Fields }
+func (f FormConfig) GetTitle() string       { return f.Title }
+func (f FormConfig) GetFields() []FormField { return f.Fields }
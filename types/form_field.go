@@ -1,13 +1,203 @@
 package types
 
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FormField is a field of a form. Its name keys the value of the field in the FormResult, so it
+// must be unique and stable, like the name of the flag or struct field it edits.
+type FormField interface {
+	FieldDefinition
+	Name() string
+	Placeholder() string
+	Type() FieldType
+	Value() string
+	IsRequired() bool
+	MinValue() int
+	MaxValue() int
+	Validate(value string) error
+}
+
 type FormFields struct {
 	Title  string
-	Fields []FormInputObject[any]
+	Fields []FormField
 }
 
 func (f FormFields) InputType() string {
 	return f.Title
 }
-func (f FormFields) Inputs() []FormInputObject[any] {
+func (f FormFields) Inputs() []FormField {
 	return f.Fields
 }
+
+// FormFieldNames returns the names keying the values and errors of the fields. A field without a
+// name, or repeating the name of an earlier field, is named by its position instead, field3 for the
+// third, so it does not overwrite another field.
+func FormFieldNames(fields []FormField) []string {
+	names := make([]string, len(fields))
+	used := make(map[string]bool)
+	for i, field := range fields {
+		if name := field.Name(); name != "" && !used[name] {
+			names[i], used[name] = name, true
+		}
+	}
+	for i := range fields {
+		if names[i] != "" {
+			continue
+		}
+		name := fmt.Sprintf("field%d", i+1)
+		for used[name] {
+			name += "_"
+		}
+		names[i], used[name] = name, true
+	}
+	return names
+}
+
+// FormFieldDetails is implemented by fields with a label, a help text shown while they are focused,
+// or a section of the form they belong to.
+type FormFieldDetails interface {
+	Label() string
+	Help() string
+	Group() string
+}
+
+// FormFieldOptions is implemented by fields whose value is chosen from a list of options: one of
+// them, or any number of them when MultiSelect is true.
+type FormFieldOptions interface {
+	Options() []string
+	MultiSelect() bool
+}
+
+// InputField is a field of a form. Nm names its value in the form result and defaults to the
+// placeholder. Min and Max bound the length of the value, the number itself for int fields, or the
+// number of selected options for multi-selects, and Err replaces the validation messages. Rules are
+// checked after the other validations.
+//
+// Bool fields are edited with a toggle. Fields with options, from Opts or the OptsFn provider, are
+// edited with a select, or with a multi-select when Multi is set.
+type InputField struct {
+	Nm     string             `json:"name" yaml:"name"`
+	Ph     string             `json:"placeholder" yaml:"placeholder"`
+	Lbl    string             `json:"label" yaml:"label"`
+	Hlp    string             `json:"help" yaml:"help"`
+	Grp    string             `json:"group" yaml:"group"`
+	Tp     FieldType          `json:"type" yaml:"type"`
+	Val    string             `json:"value" yaml:"value"`
+	Req    bool               `json:"required" yaml:"required"`
+	Min    int                `json:"min" yaml:"min"`
+	Max    int                `json:"max" yaml:"max"`
+	Err    string             `json:"error" yaml:"error"`
+	Rules  []ValidationRule   `json:"rules" yaml:"rules"`
+	Opts   []string           `json:"options" yaml:"options"`
+	OptsFn func() []string    `json:"-" yaml:"-"`
+	Multi  bool               `json:"multiple" yaml:"multiple"`
+	Vld    func(string) error `json:"-" yaml:"-"`
+}
+
+func (f *InputField) Name() string {
+	if f.Nm != "" {
+		return f.Nm
+	}
+	return f.Ph
+}
+func (f *InputField) Placeholder() string { return f.Ph }
+func (f *InputField) Type() FieldType {
+	if f.Tp == "" {
+		return FieldText
+	}
+	return f.Tp
+}
+func (f *InputField) Value() string       { return f.Val }
+func (f *InputField) IsRequired() bool    { return f.Req }
+func (f *InputField) MinValue() int       { return f.Min }
+func (f *InputField) MaxValue() int       { return f.Max }
+func (f *InputField) Label() string       { return f.Lbl }
+func (f *InputField) Help() string        { return f.Hlp }
+func (f *InputField) Group() string       { return f.Grp }
+func (f *InputField) MultiSelect() bool   { return f.Multi }
+func (f *InputField) Description() string { return "Input Field " + f.Name() }
+func (f *InputField) String() string      { return f.Val }
+
+// Options returns the options of the field, from its provider when it has one.
+func (f *InputField) Options() []string {
+	if f.OptsFn != nil {
+		return f.OptsFn()
+	}
+	return f.Opts
+}
+
+// Validate checks the value against the required flag, the bounds, the options, the custom
+// validation and the validation rules.
+func (f *InputField) Validate(value string) error {
+	err := f.validate(value)
+	if err != nil && f.Err != "" {
+		return errors.New(f.Err)
+	}
+	return err
+}
+
+func (f *InputField) validate(value string) error {
+	if err := ValidateRules(value, f.fieldRules()...); err != nil {
+		return err
+	}
+	if err := f.validateOptions(value); err != nil {
+		return err
+	}
+	if value != "" && f.Vld != nil {
+		if err := f.Vld(value); err != nil {
+			return err
+		}
+	}
+	return ValidateRules(value, f.Rules...)
+}
+
+// validateOptions checks that the value, or every item of a multi-select, is one of the options of
+// a field that has some.
+func (f *InputField) validateOptions(value string) error {
+	options := f.Options()
+	if len(options) == 0 {
+		return nil
+	}
+	values := []string{strings.TrimSpace(value)}
+	if f.Multi {
+		values = SplitFormList(value)
+	}
+	for _, v := range values {
+		if v != "" && !slices.Contains(options, v) {
+			return ErrInvalidOption.format(v)
+		}
+	}
+	return nil
+}
+
+// fieldRules returns the rules implied by the required flag, the type and the bounds of the field.
+// Min and Max only bound the field when positive, as zero means unset; zero and negative bounds are
+// given as min or max rules, as StructFormFields does for tags.
+func (f *InputField) fieldRules() []ValidationRule {
+	var rules []ValidationRule
+	if f.Req {
+		rules = append(rules, Required)
+	}
+	min, max := MinLen, MaxLen
+	switch f.Type() {
+	case FieldInt:
+		rules = append(rules, Integer)
+		min, max = Min, Max
+	case FieldBool:
+		rules = append(rules, Boolean)
+	}
+	if f.Multi {
+		min, max = MinItems, MaxItems
+	}
+	if f.Min > 0 {
+		rules = append(rules, ValidationRule(fmt.Sprintf("%s:%d", min, f.Min)))
+	}
+	if f.Max > 0 {
+		rules = append(rules, ValidationRule(fmt.Sprintf("%s:%d", max, f.Max)))
+	}
+	return rules
+}
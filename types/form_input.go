@@ -115,21 +115,15 @@ func (s *Input[T]) SetValidation(validation func(string, func(interface{}) error
 func (s *Input[T]) SetValidationRules(rules []ValidationRule) { s.ValidationRulesVal = rules }
 func (s *Input[T]) ValidationRules() []ValidationRule         { return s.ValidationRulesVal }
 func (s *Input[T]) Validate() error {
-	if s.ValidationRulesVal == nil {
-		return nil
+	rules := s.ValidationRulesVal
+	if s.Req {
+		rules = append([]ValidationRule{Required}, rules...)
 	}
-	for _, rule := range s.ValidationRulesVal {
-		if s.Val != nil {
-			if err := rule.Validate(s.String(), nil); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+	return ValidateRules(s.String(), rules...)
 }
 func (s *Input[T]) String() string {
 	if s != nil {
-		if s.Val != nil {
+		if any(s.Val) != nil {
 			if s.GetType().Kind() == reflect.String {
 				v := s.GetValue()
 				vv := reflect.ValueOf(v)
@@ -144,7 +138,7 @@ func (s *Input[T]) String() string {
 
 func (s *Input[T]) FromString(str string) error {
 	if s != nil {
-		if s.Val != nil {
+		if any(s.Val) != nil {
 			v := s.GetValue()
 			vv := reflect.ValueOf(v)
 			vv.SetString(str)
@@ -182,7 +176,8 @@ func NewFormInputFromBytes[T FormInputObject[T]](b []byte) FormInput[T] {
 func NewInputObject[T any](t T) *InputObject[T]        { return &InputObject[T]{Val: t} }
 func NewFormInputObject[T any](t T) FormInputObject[T] { return NewInputObject[T](t) }
 func NewFormInputObjectFromMap[T any](m map[string]interface{}) FormInputObject[T] {
-	return NewInputObject[T](m["value"])
+	v, _ := m["value"].(T)
+	return NewInputObject[T](v)
 }
 func NewFormInputObjectFromString[T any](str string) FormInputObject[T] {
 	v := reflect.ValueOf(str)
@@ -0,0 +1,342 @@
+package types
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formTimeLayouts are the layouts FormResult.Time accepts, tried in order.
+var formTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02", "15:04:05", "15:04"}
+
+// FormResult holds the submitted values of a form, keyed by field name. Text fields submit strings;
+// the getters convert them to the type the caller expects.
+type FormResult struct {
+	names  []string
+	values map[string]interface{}
+}
+
+// NewFormResult creates an empty form result.
+func NewFormResult() *FormResult {
+	return &FormResult{values: make(map[string]interface{})}
+}
+
+// Set stores the value of the named field, keeping the order in which fields were first set.
+func (r *FormResult) Set(name string, value interface{}) {
+	if _, ok := r.values[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.values[name] = value
+}
+
+// Names returns the field names in form order.
+func (r *FormResult) Names() []string { return append([]string(nil), r.names...) }
+
+// Has reports whether the form has a field with the given name.
+func (r *FormResult) Has(name string) bool {
+	_, ok := r.values[name]
+	return ok
+}
+
+// Value returns the raw value of the named field.
+func (r *FormResult) Value(name string) (interface{}, bool) {
+	value, ok := r.values[name]
+	return value, ok
+}
+
+// String returns the value of the named field as text, or "" when there is no such field.
+func (r *FormResult) String(name string) string {
+	switch v := r.values[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ",")
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Int returns the value of the named field as an integer.
+func (r *FormResult) Int(name string) (int, error) {
+	value, err := r.lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(r.String(name)))
+	if err != nil {
+		return 0, fmt.Errorf("form field %q: %q is not an integer", name, r.String(name))
+	}
+	return i, nil
+}
+
+// Bool returns the value of the named field as a boolean. Besides the values strconv.ParseBool
+// accepts, yes/no, y/n and on/off are understood; an empty value is false.
+func (r *FormResult) Bool(name string) (bool, error) {
+	value, err := r.lookup(name)
+	if err != nil {
+		return false, err
+	}
+	if b, ok := value.(bool); ok {
+		return b, nil
+	}
+	b, err := ParseFormBool(r.String(name))
+	if err != nil {
+		return false, fmt.Errorf("form field %q: %q is not a boolean", name, r.String(name))
+	}
+	return b, nil
+}
+
+// ParseFormBool reads yes/no, y/n, on/off and the values strconv.ParseBool accepts. An empty value
+// is false.
+func ParseFormBool(text string) (bool, error) {
+	switch text = strings.ToLower(strings.TrimSpace(text)); text {
+	case "", "no", "n", "off":
+		return false, nil
+	case "yes", "y", "on":
+		return true, nil
+	}
+	return strconv.ParseBool(text)
+}
+
+// Time returns the value of the named field as a time, parsed as RFC 3339, a date with or without a
+// time of day, or a time of day alone.
+func (r *FormResult) Time(name string) (time.Time, error) {
+	value, err := r.lookup(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t, ok := value.(time.Time); ok {
+		return t, nil
+	}
+	text := strings.TrimSpace(r.String(name))
+	for _, layout := range formTimeLayouts {
+		if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("form field %q: %q is not a date or time", name, text)
+}
+
+// Strings returns the value of the named field as a list. Text values are split on commas and the
+// items trimmed; an empty value is an empty list.
+func (r *FormResult) Strings(name string) []string {
+	switch v := r.values[name].(type) {
+	case []string:
+		return append([]string(nil), v...)
+	case nil:
+		return nil
+	}
+	return SplitFormList(r.String(name))
+}
+
+// SplitFormList splits the text of a list field on commas and trims the items. An empty text is an
+// empty list.
+func SplitFormList(text string) []string {
+	if text = strings.TrimSpace(text); text == "" {
+		return []string{}
+	}
+	items := strings.Split(text, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
+
+// Map returns the values of the form as text, keyed by field name.
+func (r *FormResult) Map() map[string]string {
+	m := make(map[string]string, len(r.names))
+	for _, name := range r.names {
+		m[name] = r.String(name)
+	}
+	return m
+}
+
+func (r *FormResult) lookup(name string) (interface{}, error) {
+	value, ok := r.values[name]
+	if !ok {
+		return nil, fmt.Errorf("form has no field %q", name)
+	}
+	return value, nil
+}
+
+// Decode stores the values of the form in the struct v points to. Exported fields are matched to
+// form fields by name, ignoring case, dashes and underscores, so a `dry-run` field fills DryRun;
+// the name option of an `xtui` tag replaces the Go name. Nested structs are matched with dotted
+// names such as `Server.Port`; a nil pointer to a struct is allocated when one of its fields is
+// in the form. Struct fields without a matching form field are left untouched.
+func (r *FormResult) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode form result: %T is not a pointer to a struct", v)
+	}
+	keys := make(map[string]string, len(r.names))
+	for _, name := range r.names {
+		keys[normalizeFieldName(name)] = name
+	}
+	return r.decodeStruct(rv.Elem(), "", keys)
+}
+
+func (r *FormResult) decodeStruct(rv reflect.Value, prefix string, keys map[string]string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := ParseFormTag(sf.Tag.Get(FormTagKey))
+		if !sf.IsExported() || tag.Skip {
+			continue
+		}
+		path := prefix + formFieldName(sf, tag)
+		field := rv.Field(i)
+		name, ok := keys[normalizeFieldName(path)]
+		if !ok {
+			if isNestedStruct(field.Type()) {
+				if err := r.decodeNested(field, path+".", keys); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := r.assign(field, name); err != nil {
+			return fmt.Errorf("decode form field %q into %s: %w", name, path, err)
+		}
+	}
+	return nil
+}
+
+// decodeNested decodes the fields of a nested struct. A nil pointer to a struct is allocated when
+// the form has a field for it, and stays nil otherwise.
+func (r *FormResult) decodeNested(field reflect.Value, prefix string, keys map[string]string) error {
+	if field.Kind() != reflect.Pointer {
+		return r.decodeStruct(field, prefix, keys)
+	}
+	if field.IsNil() {
+		if !hasFieldPrefix(keys, prefix) {
+			return nil
+		}
+		field.Set(reflect.New(field.Type().Elem()))
+	}
+	return r.decodeStruct(field.Elem(), prefix, keys)
+}
+
+// hasFieldPrefix reports whether a form field name starts with prefix, compared like field names.
+func hasFieldPrefix(keys map[string]string, prefix string) bool {
+	prefix = normalizeFieldName(prefix)
+	for key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// assign converts the value of the named field to the type of dst and stores it.
+func (r *FormResult) assign(dst reflect.Value, name string) error {
+	if text := strings.TrimSpace(r.String(name)); text == "" && (dst.Type() == timeType || dst.Type() == durationType) {
+		dst.SetZero()
+		return nil
+	}
+	switch dst.Interface().(type) {
+	case time.Time:
+		t, err := r.Time(name)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	case time.Duration:
+		d, err := time.ParseDuration(strings.TrimSpace(r.String(name)))
+		if err != nil {
+			return err
+		}
+		dst.SetInt(int64(d))
+		return nil
+	}
+	if dst.CanAddr() {
+		if u, ok := dst.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(r.String(name)))
+		}
+	}
+
+	text := strings.TrimSpace(r.String(name))
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(r.String(name))
+	case reflect.Bool:
+		b, err := r.Bool(name)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if text == "" {
+			dst.SetInt(0)
+			return nil
+		}
+		i, err := strconv.ParseInt(text, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if text == "" {
+			dst.SetUint(0)
+			return nil
+		}
+		u, err := strconv.ParseUint(text, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		if text == "" {
+			dst.SetFloat(0)
+			return nil
+		}
+		f, err := strconv.ParseFloat(text, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+	case reflect.Slice:
+		items := r.Strings(name)
+		slice := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, item := range items {
+			item := &FormResult{names: []string{name}, values: map[string]interface{}{name: item}}
+			if err := item.assign(slice.Index(i), name); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+	case reflect.Pointer:
+		// an empty value leaves an optional field unset
+		if text == "" {
+			dst.SetZero()
+			return nil
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return r.assign(dst.Elem(), name)
+	default:
+		return fmt.Errorf("unsupported type %s", dst.Type())
+	}
+	return nil
+}
+
+// normalizeFieldName lowercases a field name and drops dashes and underscores, so flag, tag and
+// struct field spellings of the same name compare equal.
+func normalizeFieldName(name string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+}
@@ -0,0 +1,162 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type decodeServer struct {
+	Host string
+	Port uint16
+}
+
+type decodeConfig struct {
+	Name     string
+	DryRun   bool
+	Retries  int8
+	Ratio    float64
+	Timeout  time.Duration
+	Since    time.Time
+	Tags     []string
+	Ports    []int
+	Limit    *int
+	Env      string `xtui:"name=environment"`
+	Secret   string `xtui:"-"`
+	Server   decodeServer
+	Fallback *decodeServer
+	Backup   *decodeServer
+}
+
+func newTestFormResult(values map[string]string) *FormResult {
+	r := NewFormResult()
+	for name, value := range values {
+		r.Set(name, value)
+	}
+	return r
+}
+
+func TestFormResultDecode(t *testing.T) {
+	r := newTestFormResult(map[string]string{
+		"name":          "web",
+		"dry-run":       "yes",
+		"retries":       "-3",
+		"ratio":         "0.5",
+		"timeout":       "1m30s",
+		"since":         "2024-02-29",
+		"tags":          "a, b ,c",
+		"ports":         "80,443",
+		"limit":         "10",
+		"environment":   "prod",
+		"Secret":        "hunter2",
+		"Server.Host":   "localhost",
+		"server.port":   "8080",
+		"Fallback.Host": "backup",
+	})
+	var got decodeConfig
+	if err := r.Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	limit := 10
+	want := decodeConfig{
+		Name:     "web",
+		DryRun:   true,
+		Retries:  -3,
+		Ratio:    0.5,
+		Timeout:  90 * time.Second,
+		Since:    time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local),
+		Tags:     []string{"a", "b", "c"},
+		Ports:    []int{80, 443},
+		Limit:    &limit,
+		Env:      "prod",
+		Server:   decodeServer{Host: "localhost", Port: 8080},
+		Fallback: &decodeServer{Host: "backup"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode = %+v, want %+v", got, want)
+	}
+}
+
+func TestFormResultDecodeEmptyValues(t *testing.T) {
+	limit := 5
+	got := decodeConfig{
+		Name:    "kept",
+		Retries: 2,
+		Timeout: time.Second,
+		Since:   time.Now(),
+		Limit:   &limit,
+	}
+	r := newTestFormResult(map[string]string{
+		"retries": " ",
+		"timeout": "",
+		"since":   "",
+		"limit":   "",
+		"tags":    "",
+	})
+	if err := r.Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := decodeConfig{Name: "kept", Tags: []string{}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode = %+v, want %+v", got, want)
+	}
+
+	var unset decodeConfig
+	if err := newTestFormResult(map[string]string{"limit": ""}).Decode(&unset); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if unset.Limit != nil {
+		t.Errorf("Decode of an empty value allocated Limit = %v, want nil", *unset.Limit)
+	}
+}
+
+func TestFormResultDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]string
+	}{
+		{name: "int8 overflow", values: map[string]string{"retries": "300"}},
+		{name: "uint16 overflow", values: map[string]string{"server.port": "70000"}},
+		{name: "negative unsigned", values: map[string]string{"server.port": "-1"}},
+		{name: "bad bool", values: map[string]string{"dry-run": "maybe"}},
+		{name: "bad duration", values: map[string]string{"timeout": "5 minutes"}},
+		{name: "bad date", values: map[string]string{"since": "29/02/2024"}},
+		{name: "bad list item", values: map[string]string{"ports": "80,http"}},
+	}
+	for _, tt := range tests {
+		var got decodeConfig
+		if err := newTestFormResult(tt.values).Decode(&got); err == nil {
+			t.Errorf("%s: Decode(%v) = nil, want an error", tt.name, tt.values)
+		}
+	}
+
+	var notStruct int
+	if err := NewFormResult().Decode(&notStruct); err == nil {
+		t.Error("Decode into *int = nil, want an error")
+	}
+	if err := NewFormResult().Decode(decodeConfig{}); err == nil {
+		t.Error("Decode into a struct value = nil, want an error")
+	}
+}
+
+func TestParseFormBool(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    bool
+		wantErr bool
+	}{
+		{in: "", want: false},
+		{in: "Yes", want: true},
+		{in: " on ", want: true},
+		{in: "n", want: false},
+		{in: "1", want: true},
+		{in: "false", want: false},
+		{in: "maybe", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormBool(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormBool(%q) = %v, %v, want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
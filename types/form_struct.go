@@ -0,0 +1,308 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormTagKey is the struct tag read by StructFormFields and FormResult.Decode.
+const FormTagKey = "xtui"
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// FormTag holds the options of an `xtui` struct tag, such as
+// `xtui:"label=Port,required,min=1,max=65535,type=int,help=Port to listen on"`.
+//
+// Options are separated by commas: name, label, help, placeholder and type take a value after an
+// equals sign; options lists the values to choose from, separated by bars, as in
+// `options=dev|staging|prod`; min and max bound numbers, the length of text, or the number of
+// options chosen for a slice, and are nil when not set; required, email, url, ip and port are
+// flags, and the other validation rules, including registered ones, take their parameter after an
+// equals sign, as in `regexp=^[a-z]+$` or `min_len=8`. A comma followed by something other than an
+// option belongs to the previous value, so help texts and patterns may contain commas. The tag
+// `xtui:"-"` leaves the field out.
+type FormTag struct {
+	Name        string
+	Label       string
+	Help        string
+	Placeholder string
+	Type        FieldType
+	Options     []string
+	Required    bool
+	Min, Max    *int
+	Rules       []ValidationRule
+	Skip        bool
+}
+
+// ParseFormTag parses the value of an `xtui` struct tag.
+func ParseFormTag(tag string) FormTag {
+	var t FormTag
+	if tag == "-" {
+		t.Skip = true
+		return t
+	}
+	var options []string
+	for _, part := range strings.Split(tag, ",") {
+		key, _, _ := strings.Cut(part, "=")
+		if len(options) > 0 && !isFormTagOption(strings.TrimSpace(key)) {
+			options[len(options)-1] += "," + part
+			continue
+		}
+		options = append(options, part)
+	}
+	for _, option := range options {
+		key, value, _ := strings.Cut(option, "=")
+		switch key = strings.TrimSpace(key); key {
+		case "name":
+			t.Name = value
+		case "label":
+			t.Label = value
+		case "help":
+			t.Help = value
+		case "placeholder":
+			t.Placeholder = value
+		case "type":
+			t.Type = FieldType(value)
+		case "options":
+			t.Options = strings.Split(value, "|")
+		case "required":
+			t.Required = true
+		case "min":
+			t.Min = parseFormTagBound(value)
+		case "max":
+			t.Max = parseFormTagBound(value)
+		case "":
+		default:
+			rule := ValidationRule(key)
+			if value != "" {
+				rule = ValidationRule(key + ":" + value)
+			}
+			t.Rules = append(t.Rules, rule)
+		}
+	}
+	return t
+}
+
+// parseFormTagBound parses the value of a min or max option, or returns nil when it is not an
+// integer.
+func parseFormTagBound(value string) *int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+func isFormTagOption(key string) bool {
+	switch key {
+	case "name", "label", "help", "placeholder", "type", "options":
+		return true
+	}
+	return key != "" && ValidationRule(key).Known()
+}
+
+// formFieldName returns the name of a struct field in the form: the name option of its tag, or the
+// Go field name.
+func formFieldName(sf reflect.StructField, tag FormTag) string {
+	if tag.Name != "" {
+		return tag.Name
+	}
+	return sf.Name
+}
+
+// StructFormFields builds the fields of a form editing the struct v points to. Every exported field
+// becomes an input named after its dotted path, like `Server.Port`, configured by its `xtui` tag
+// (see FormTag). Nested structs, and pointers to them, become sections titled by their label,
+// booleans toggles, and slices list fields edited as comma separated values. Fields with options
+// are chosen from a select, or from a multi-select for slices. Fields of other kinds, such as maps,
+// are left out.
+func StructFormFields(v interface{}) ([]FormField, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("form for %T: not a pointer to a struct", v)
+	}
+	var fields []FormField
+	structFormFields(rv.Elem(), "", "", &fields)
+	return fields, nil
+}
+
+func structFormFields(rv reflect.Value, prefix, group string, fields *[]FormField) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := ParseFormTag(sf.Tag.Get(FormTagKey))
+		if !sf.IsExported() || tag.Skip {
+			continue
+		}
+		name := prefix + formFieldName(sf, tag)
+		label := tag.Label
+		if label == "" {
+			label = sf.Name
+		}
+		fv := rv.Field(i)
+		if isNestedStruct(fv.Type()) {
+			if fv.Kind() == reflect.Pointer {
+				// a nil pointer shows the zero value; Decode allocates it
+				if fv.IsNil() {
+					fv = reflect.New(fv.Type().Elem())
+				}
+				fv = fv.Elem()
+			}
+			section := label
+			if group != "" {
+				section = group + " / " + label
+			}
+			structFormFields(fv, name+".", section, fields)
+			continue
+		}
+		typ, ok := structFieldType(fv.Type())
+		if !ok {
+			continue
+		}
+		if tag.Type != "" {
+			typ = tag.Type
+		}
+		kind := indirectType(fv.Type()).Kind()
+		field := &InputField{
+			Nm:    name,
+			Ph:    tag.Placeholder,
+			Lbl:   label,
+			Hlp:   tag.Help,
+			Grp:   group,
+			Tp:    typ,
+			Val:   structFieldValue(fv, typ),
+			Req:   tag.Required,
+			Rules: tag.Rules,
+			Opts:  tag.Options,
+			Multi: len(tag.Options) > 0 && kind == reflect.Slice,
+		}
+		if kind == reflect.Float32 || kind == reflect.Float64 {
+			field.Rules = append([]ValidationRule{Number}, field.Rules...)
+		}
+		field.Rules = append(typeRules(fv.Type()), field.Rules...)
+		// the bounds become rules of their own, so zero and negative bounds are checked too
+		min, max := boundRules(field, kind)
+		if tag.Min != nil {
+			field.Rules = append(field.Rules, ValidationRule(fmt.Sprintf("%s:%d", min, *tag.Min)))
+		}
+		if tag.Max != nil {
+			field.Rules = append(field.Rules, ValidationRule(fmt.Sprintf("%s:%d", max, *tag.Max)))
+		}
+		*fields = append(*fields, field)
+	}
+}
+
+// typeRules returns the rules checking that a value fits the Go type of a struct field, so the form
+// rejects what Decode could not store: the range of sized and unsigned integers, durations and
+// dates.
+func typeRules(t reflect.Type) []ValidationRule {
+	t = indirectType(t)
+	switch t {
+	case timeType:
+		return []ValidationRule{Date}
+	case durationType:
+		return []ValidationRule{Duration}
+	}
+	bound := func(rule ValidationRule, n any) ValidationRule {
+		return ValidationRule(fmt.Sprintf("%s:%d", rule, n))
+	}
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return []ValidationRule{bound(Min, -1<<(t.Bits()-1)), bound(Max, 1<<(t.Bits()-1)-1)}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return []ValidationRule{bound(Min, 0), bound(Max, 1<<t.Bits()-1)}
+	case reflect.Uint, reflect.Uint64:
+		return []ValidationRule{bound(Min, 0)}
+	}
+	return nil
+}
+
+// boundRules returns the rules checking the min and max bounds of a field of the given kind: the
+// number for numbers, the count of items for multi-selects and the length of the text otherwise.
+func boundRules(field *InputField, kind reflect.Kind) (min, max ValidationRule) {
+	switch {
+	case field.Multi:
+		return MinItems, MaxItems
+	case field.Type() == FieldInt, kind == reflect.Float32, kind == reflect.Float64:
+		return Min, Max
+	}
+	return MinLen, MaxLen
+}
+
+// isNestedStruct reports whether values of type t are edited as a section of their own: structs
+// other than time.Time, and pointers to them.
+func isNestedStruct(t reflect.Type) bool {
+	t = indirectType(t)
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// structFieldType returns the form field type editing values of type t, or false when the type
+// cannot be edited in a form.
+func structFieldType(t reflect.Type) (FieldType, bool) {
+	t = indirectType(t)
+	switch {
+	case t == timeType:
+		return FieldDate, true
+	case t == durationType:
+		return FieldText, true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Float32, reflect.Float64:
+		return FieldText, true
+	case reflect.Bool:
+		return FieldBool, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return FieldInt, true
+	case reflect.Slice:
+		if _, ok := structFieldType(t.Elem()); ok && t.Elem().Kind() != reflect.Slice {
+			return FieldList, true
+		}
+	}
+	return "", false
+}
+
+// structFieldValue formats the current value of a struct field for its input.
+func structFieldValue(fv reflect.Value, typ FieldType) string {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return ""
+		}
+		fv = fv.Elem()
+	}
+	switch v := fv.Interface().(type) {
+	case time.Time:
+		switch {
+		case v.IsZero():
+			return ""
+		case typ == FieldTime:
+			return v.Format("15:04")
+		case v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0:
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02 15:04:05")
+	case time.Duration:
+		return v.String()
+	}
+	if fv.Kind() == reflect.Slice {
+		items := make([]string, fv.Len())
+		for i := range items {
+			items[i] = structFieldValue(fv.Index(i), "")
+		}
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprint(fv.Interface())
+}
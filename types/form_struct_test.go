@@ -0,0 +1,99 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFormTag(t *testing.T) {
+	min, max := 1, 65535
+	tests := []struct {
+		tag  string
+		want FormTag
+	}{
+		{tag: "-", want: FormTag{Skip: true}},
+		{
+			tag:  "label=Port,required,min=1,max=65535,help=Port to listen on, or 0",
+			want: FormTag{Label: "Port", Required: true, Min: &min, Max: &max, Help: "Port to listen on, or 0"},
+		},
+		{
+			tag:  "options=dev|staging|prod,regexp=^[a-z]{2,8}$,email",
+			want: FormTag{Options: []string{"dev", "staging", "prod"}, Rules: []ValidationRule{"regexp:^[a-z]{2,8}$", Email}},
+		},
+		{tag: "name=dry-run,min=x", want: FormTag{Name: "dry-run"}},
+	}
+	for _, tt := range tests {
+		if got := ParseFormTag(tt.tag); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFormTag(%q) = %+v, want %+v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+type structFormSample struct {
+	Offset  int           `xtui:"min=-10,max=10"`
+	Level   uint8         `xtui:"max=100"`
+	Count   uint          `xtui:"min=0"`
+	Small   int16         ``
+	Name    string        `xtui:"required,min=2"`
+	Roles   []string      `xtui:"options=admin|user,min=1"`
+	Timeout time.Duration ``
+	Since   time.Time     ``
+	Skipped string        `xtui:"-"`
+	Labels  map[string]string
+}
+
+func TestStructFormFieldsValidation(t *testing.T) {
+	fields, err := StructFormFields(&structFormSample{})
+	if err != nil {
+		t.Fatalf("StructFormFields: %v", err)
+	}
+	byName := make(map[string]FormField, len(fields))
+	for _, field := range fields {
+		byName[field.Name()] = field
+	}
+	for _, name := range []string{"Skipped", "Labels"} {
+		if _, ok := byName[name]; ok {
+			t.Errorf("StructFormFields has a field %s, want it left out", name)
+		}
+	}
+
+	tests := []struct {
+		field, value string
+		want         error
+	}{
+		{field: "Offset", value: "-10"},
+		{field: "Offset", value: "0"},
+		{field: "Offset", value: "-11", want: ErrInvalidMin},
+		{field: "Offset", value: "11", want: ErrInvalidMax},
+		{field: "Level", value: "100"},
+		{field: "Level", value: "101", want: ErrInvalidMax},
+		{field: "Level", value: "-1", want: ErrInvalidMin},
+		{field: "Count", value: "0"},
+		{field: "Count", value: "-1", want: ErrInvalidMin},
+		{field: "Small", value: "-32768"},
+		{field: "Small", value: "32768", want: ErrInvalidMax},
+		{field: "Name", value: "", want: ErrRequired},
+		{field: "Name", value: "a", want: ErrInvalidMinLen},
+		{field: "Name", value: "ab"},
+		{field: "Roles", value: "admin"},
+		{field: "Roles", value: "", want: ErrInvalidMinItems},
+		{field: "Roles", value: "root", want: ErrInvalidOption},
+		{field: "Timeout", value: "1h"},
+		{field: "Timeout", value: "5 minutes", want: ErrInvalidDuration},
+		{field: "Since", value: "2024-02-29 13:45"},
+		{field: "Since", value: "yesterday", want: ErrInvalidDate},
+	}
+	for _, tt := range tests {
+		field, ok := byName[tt.field]
+		if !ok {
+			t.Errorf("StructFormFields has no field %s", tt.field)
+			continue
+		}
+		err := field.Validate(tt.value)
+		if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("%s.Validate(%q) = %v, want %v", tt.field, tt.value, err, tt.want)
+		}
+	}
+}
@@ -95,12 +95,30 @@ const (
 func (f FieldType) Description() string { return "Field Type " + string(f) }
 func (f FieldType) String() string      { return string(f) }
 
+// Table Column Types, used as hints to compare and format cell values
+
+type ColumnType string
+
+const (
+	ColumnString ColumnType = "string"
+	ColumnInt    ColumnType = "int"
+	ColumnFloat  ColumnType = "float"
+	ColumnSemver ColumnType = "semver"
+	ColumnBytes  ColumnType = "bytes"
+	ColumnTime   ColumnType = "time"
+)
+
+func (c ColumnType) Description() string { return "Column Type " + string(c) }
+func (c ColumnType) String() string      { return string(c) }
+
 // Field Rules and Validation Types
 
 type FieldRule interface {
 	Validate(value string) error
 }
 
+// ValidationRule names a validation, with an optional parameter after a colon, such as `min_len:8`.
+// See ValidationRule.Validate and RegisterValidationRule.
 type ValidationRule string
 
 const (
@@ -115,24 +133,14 @@ const (
 	MaxLen   ValidationRule = "max_len"
 	Regexp   ValidationRule = "regexp"
 	Pattern  ValidationRule = "pattern"
+	Integer  ValidationRule = "int"
+	Number   ValidationRule = "number"
+	Boolean  ValidationRule = "bool"
+	MinItems ValidationRule = "min_items"
+	MaxItems ValidationRule = "max_items"
+	Duration ValidationRule = "duration"
+	Date     ValidationRule = "date"
 )
 
 func (v ValidationRule) Description() string { return "Validation Rule " + string(v) }
 func (v ValidationRule) String() string      { return string(v) }
-func (v ValidationRule) Validate(value string, customCheck func(interface{}) error) error {
-	switch v {
-	case Required:
-		if value == "" {
-			return ErrRequired
-		}
-		// TODO: Add more native basic validation rules
-		//default:
-		//	if customCheck != nil {
-		//		return customCheck(v)
-		//	}
-	}
-	if customCheck != nil {
-		return customCheck(v)
-	}
-	return nil
-}
@@ -4,3 +4,29 @@ type TableDataHandler interface {
 	GetHeaders() []string
 	GetRows() [][]string
 }
+
+// TableDataNotifier is implemented by table handlers whose data changes over time. A value sent on
+// the Changed channel makes a running table reload its headers and rows from the handler.
+type TableDataNotifier interface {
+	TableDataHandler
+	Changed() <-chan struct{}
+}
+
+// TableRowProvider is a paged data source for tables too large to keep in memory. The table only
+// asks for the rows it is about to display, so RowsAt may read them lazily from disk or a command.
+type TableRowProvider interface {
+	GetHeaders() []string
+	RowsAt(offset, limit int) [][]string
+	Count() int
+}
+
+// TableColumnTyper is implemented by table handlers that know the type of their columns. Exporters
+// use it to write numbers and dates as typed values.
+type TableColumnTyper interface {
+	GetColumnTypes() map[string]ColumnType
+}
+
+// TableTitler is implemented by table handlers that have a title, used by report style exports.
+type TableTitler interface {
+	GetTitle() string
+}
@@ -0,0 +1,353 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationFunc checks a value against a rule. param is the text after the colon of the rule,
+// such as "8" in `min_len:8`, or "" when the rule has none.
+type ValidationFunc func(value, param string) error
+
+var (
+	validationMu    sync.RWMutex
+	validationRules = map[string]ValidationFunc{
+		string(Required): validateRequired,
+		string(Email):    validateEmail,
+		string(Url):      validateURL,
+		string(IP):       validateIP,
+		string(Port):     validatePort,
+		string(Min):      validateMin,
+		string(Max):      validateMax,
+		string(MinLen):   validateMinLen,
+		string(MaxLen):   validateMaxLen,
+		string(Regexp):   validateRegexp,
+		string(Pattern):  validatePattern,
+		string(Integer):  validateInteger,
+		string(Number):   validateNumber,
+		string(Boolean):  validateBoolean,
+		string(MinItems): validateMinItems,
+		string(MaxItems): validateMaxItems,
+		string(Duration): validateDuration,
+		string(Date):     validateDate,
+	}
+
+	// compiledRegexps caches the expressions of regexp rules, which run on every validation.
+	compiledRegexps sync.Map
+)
+
+// RegisterValidationRule registers a custom rule, or replaces a built-in one. Errors returned by fn
+// that are not FormErrors are reported under the rule name.
+func RegisterValidationRule(name string, fn ValidationFunc) {
+	validationMu.Lock()
+	defer validationMu.Unlock()
+	validationRules[name] = fn
+}
+
+// RegisterValidationAlias registers a rule made of other rules, which must all pass, so a
+// combination such as a username policy can be used by name:
+//
+//	RegisterValidationAlias("username", "min_len:3", "max_len:16", "regexp:^[a-z0-9_]+$")
+func RegisterValidationAlias(name string, rules ...ValidationRule) {
+	RegisterValidationRule(name, func(value, _ string) error {
+		return ValidateRules(value, rules...)
+	})
+}
+
+func lookupValidationRule(name string) (ValidationFunc, bool) {
+	validationMu.RLock()
+	defer validationMu.RUnlock()
+	fn, ok := validationRules[name]
+	return fn, ok
+}
+
+// Name returns the name of the rule, without its parameter.
+func (v ValidationRule) Name() string {
+	name, _, _ := strings.Cut(string(v), ":")
+	return name
+}
+
+// Param returns the parameter of the rule, the text after the first colon.
+func (v ValidationRule) Param() string {
+	_, param, _ := strings.Cut(string(v), ":")
+	return param
+}
+
+// Known reports whether every alternative of the rule is a registered rule.
+func (v ValidationRule) Known() bool {
+	for _, alternative := range v.alternatives() {
+		if _, ok := lookupValidationRule(alternative.Name()); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// alternatives splits a rule such as `ip|url` into the rules it accepts. A bar followed by something
+// other than a registered rule name belongs to the parameter, as in `regexp:^(a|b)$`.
+func (v ValidationRule) alternatives() []ValidationRule {
+	var alternatives []ValidationRule
+	for _, part := range strings.Split(string(v), "|") {
+		if _, ok := lookupValidationRule(ValidationRule(part).Name()); !ok && len(alternatives) > 0 {
+			alternatives[len(alternatives)-1] += ValidationRule("|" + part)
+			continue
+		}
+		alternatives = append(alternatives, ValidationRule(part))
+	}
+	return alternatives
+}
+
+// Validate checks the value against the rule. A rule has a name and an optional parameter after a
+// colon, as in `min_len:8` or `regexp:^[a-z]+$`, and alternatives separated by bars, as in
+// `ip|url`, pass when any of them does. Every rule but required accepts an empty value. The error
+// is a FormError with its message formatted with the parameter; when customCheck is not nil, it is
+// called with the value once the rule passed.
+func (v ValidationRule) Validate(value string, customCheck func(interface{}) error) error {
+	var err error
+	for _, alternative := range v.alternatives() {
+		if err = alternative.validate(value); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if customCheck != nil {
+		return customCheck(value)
+	}
+	return nil
+}
+
+func (v ValidationRule) validate(value string) error {
+	fn, ok := lookupValidationRule(v.Name())
+	if !ok {
+		return ErrUnknownRule.format(v.Name())
+	}
+	// an empty value passes every rule but required, and min_items, as it has no items
+	if value == "" && v.Name() != string(Required) && v.Name() != string(MinItems) {
+		return nil
+	}
+	err := fn(value, v.Param())
+	var formErr FormError
+	if err != nil && !errors.As(err, &formErr) {
+		return &formError{Rule: v.Name(), Message: err.Error()}
+	}
+	return err
+}
+
+// ValidateRules checks the value against every rule, in order, and returns the first error.
+func ValidateRules(value string, rules ...ValidationRule) error {
+	for _, rule := range rules {
+		if err := rule.Validate(value, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateRequired(value, _ string) error {
+	if strings.TrimSpace(value) == "" {
+		return ErrRequired
+	}
+	return nil
+}
+
+func validateEmail(value, _ string) error {
+	if address, err := mail.ParseAddress(value); err != nil || address.Address != value {
+		return ErrInvalidEmail
+	}
+	return nil
+}
+
+func validateURL(value, _ string) error {
+	if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" && u.Opaque == "" {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+func validateIP(value, _ string) error {
+	if net.ParseIP(value) == nil {
+		return ErrInvalidIP
+	}
+	return nil
+}
+
+func validatePort(value, _ string) error {
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		return ErrInvalidPort
+	}
+	return nil
+}
+
+func validateMin(value, param string) error {
+	limit, n, err := numberRule(value, param)
+	if err != nil {
+		return err
+	}
+	if n < limit {
+		return ErrInvalidMin.format(limit)
+	}
+	return nil
+}
+
+func validateMax(value, param string) error {
+	limit, n, err := numberRule(value, param)
+	if err != nil {
+		return err
+	}
+	if n > limit {
+		return ErrInvalidMax.format(limit)
+	}
+	return nil
+}
+
+// numberRule parses the limit of a min or max rule and the value it checks.
+func numberRule(value, param string) (limit, n float64, err error) {
+	if limit, err = strconv.ParseFloat(param, 64); err != nil {
+		return 0, 0, ErrInvalidRule.format(param)
+	}
+	if n, err = strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+		return 0, 0, ErrInvalidNumber
+	}
+	return limit, n, nil
+}
+
+func validateInteger(value, _ string) error {
+	if _, err := strconv.Atoi(strings.TrimSpace(value)); err != nil {
+		return ErrInvalidInt
+	}
+	return nil
+}
+
+func validateNumber(value, _ string) error {
+	if _, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil {
+		return ErrInvalidNumber
+	}
+	return nil
+}
+
+func validateBoolean(value, _ string) error {
+	if _, err := ParseFormBool(value); err != nil {
+		return ErrInvalidBool
+	}
+	return nil
+}
+
+func validateDuration(value, _ string) error {
+	if _, err := time.ParseDuration(strings.TrimSpace(value)); err != nil {
+		return ErrInvalidDuration
+	}
+	return nil
+}
+
+// validateDate accepts the layouts FormResult.Time parses.
+func validateDate(value, _ string) error {
+	value = strings.TrimSpace(value)
+	for _, layout := range formTimeLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return nil
+		}
+	}
+	return ErrInvalidDate
+}
+
+func validateMinLen(value, param string) error {
+	limit, err := strconv.Atoi(param)
+	if err != nil {
+		return ErrInvalidRule.format(param)
+	}
+	if utf8.RuneCountInString(value) < limit {
+		return ErrInvalidMinLen.format(limit)
+	}
+	return nil
+}
+
+func validateMaxLen(value, param string) error {
+	limit, err := strconv.Atoi(param)
+	if err != nil {
+		return ErrInvalidRule.format(param)
+	}
+	if utf8.RuneCountInString(value) > limit {
+		return ErrInvalidMaxLen.format(limit)
+	}
+	return nil
+}
+
+// validateMinItems counts the comma separated items of a list value.
+func validateMinItems(value, param string) error {
+	limit, err := strconv.Atoi(param)
+	if err != nil {
+		return ErrInvalidRule.format(param)
+	}
+	if len(SplitFormList(value)) < limit {
+		return ErrInvalidMinItems.format(limit)
+	}
+	return nil
+}
+
+func validateMaxItems(value, param string) error {
+	limit, err := strconv.Atoi(param)
+	if err != nil {
+		return ErrInvalidRule.format(param)
+	}
+	if len(SplitFormList(value)) > limit {
+		return ErrInvalidMaxItems.format(limit)
+	}
+	return nil
+}
+
+func validateRegexp(value, param string) error {
+	re, err := compileRuleRegexp(param)
+	if err != nil {
+		return ErrInvalidRule.format(param)
+	}
+	if !re.MatchString(value) {
+		return ErrInvalidRegexp.format(param)
+	}
+	return nil
+}
+
+func compileRuleRegexp(expr string) (*regexp.Regexp, error) {
+	if re, ok := compiledRegexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	compiledRegexps.Store(expr, re)
+	return re, nil
+}
+
+// validatePattern matches the value against a shell pattern, where * matches any text, ? a single
+// character and [a-z] a character class.
+func validatePattern(value, param string) error {
+	matched, err := path.Match(param, value)
+	if err != nil {
+		return ErrInvalidRule.format(param)
+	}
+	if !matched {
+		return ErrInvalidPattern.format(param)
+	}
+	return nil
+}
+
+// NewFormError creates a FormError reported under the rule name, with the message formatted with
+// args as by fmt.Sprintf.
+func NewFormError(rule, message string, args ...interface{}) FormError {
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	return &formError{Rule: rule, Message: message}
+}
@@ -0,0 +1,182 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidationRuleValidate(t *testing.T) {
+	tests := []struct {
+		rule  ValidationRule
+		value string
+		want  error
+	}{
+		{rule: Required, value: "x"},
+		{rule: Required, value: "", want: ErrRequired},
+		{rule: Required, value: "   ", want: ErrRequired},
+		{rule: Email, value: "someone@example.com"},
+		{rule: Email, value: "someone", want: ErrInvalidEmail},
+		{rule: Email, value: ""},
+		{rule: Port, value: "8080"},
+		{rule: Port, value: "70000", want: ErrInvalidPort},
+		{rule: "min:3", value: "3"},
+		{rule: "min:3", value: "2.5", want: ErrInvalidMin},
+		{rule: "min:-10", value: "-10"},
+		{rule: "min:-10", value: "-11", want: ErrInvalidMin},
+		{rule: "max:10", value: "11", want: ErrInvalidMax},
+		{rule: "max:10", value: "ten", want: ErrInvalidNumber},
+		{rule: "max:ten", value: "1", want: ErrInvalidRule},
+		{rule: "min_len:3", value: "äöü"},
+		{rule: "min_len:3", value: "ab", want: ErrInvalidMinLen},
+		{rule: "max_len:2", value: "abc", want: ErrInvalidMaxLen},
+		{rule: "min_items:2", value: "a, b"},
+		{rule: "min_items:2", value: "a", want: ErrInvalidMinItems},
+		{rule: "min_items:1", value: "", want: ErrInvalidMinItems},
+		{rule: "max_items:1", value: "a,b", want: ErrInvalidMaxItems},
+		{rule: "regexp:^[a-z]+$", value: "abc"},
+		{rule: "regexp:^[a-z]+$", value: "ABC", want: ErrInvalidRegexp},
+		{rule: "regexp:^(a|b)$", value: "b"},
+		{rule: "regexp:^(a|b)$", value: "c", want: ErrInvalidRegexp},
+		{rule: "regexp:[", value: "a", want: ErrInvalidRule},
+		{rule: "pattern:*.go", value: "main.go"},
+		{rule: "pattern:*.go", value: "main.rs", want: ErrInvalidPattern},
+		{rule: Integer, value: " 42 "},
+		{rule: Integer, value: "4.2", want: ErrInvalidInt},
+		{rule: Number, value: "4.2"},
+		{rule: Boolean, value: "yes"},
+		{rule: Boolean, value: "maybe", want: ErrInvalidBool},
+		{rule: Duration, value: "1h30m"},
+		{rule: Duration, value: "5 minutes", want: ErrInvalidDuration},
+		{rule: Date, value: "2024-02-29"},
+		{rule: Date, value: "2024-02-29 13:45"},
+		{rule: Date, value: "13:45"},
+		{rule: Date, value: "29/02/2024", want: ErrInvalidDate},
+		{rule: "ip|url", value: "10.0.0.1"},
+		{rule: "ip|url", value: "https://example.com"},
+		{rule: "ip|url", value: "example", want: ErrInvalidURL},
+		{rule: "no_such_rule", value: "x", want: ErrUnknownRule},
+	}
+	for _, tt := range tests {
+		err := tt.rule.Validate(tt.value, nil)
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s.Validate(%q) = %v, want nil", tt.rule, tt.value, err)
+			}
+			continue
+		}
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s.Validate(%q) = %v, want %v", tt.rule, tt.value, err, tt.want)
+		}
+	}
+}
+
+func TestValidationRuleNameAndParam(t *testing.T) {
+	tests := []struct {
+		rule        ValidationRule
+		name, param string
+	}{
+		{rule: Required, name: "required"},
+		{rule: "min_len:8", name: "min_len", param: "8"},
+		{rule: "regexp:^a:b$", name: "regexp", param: "^a:b$"},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Name(); got != tt.name {
+			t.Errorf("%s.Name() = %q, want %q", tt.rule, got, tt.name)
+		}
+		if got := tt.rule.Param(); got != tt.param {
+			t.Errorf("%s.Param() = %q, want %q", tt.rule, got, tt.param)
+		}
+	}
+}
+
+func TestValidationRuleKnown(t *testing.T) {
+	tests := []struct {
+		rule ValidationRule
+		want bool
+	}{
+		{rule: "ip|url", want: true},
+		{rule: "regexp:^(a|b)$", want: true},
+		{rule: "ip|no_such_rule", want: false},
+		{rule: "no_such_rule", want: false},
+		{rule: "no_such_rule|ip", want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Known(); got != tt.want {
+			t.Errorf("%s.Known() = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestValidationRuleCustomCheck(t *testing.T) {
+	calls := 0
+	check := func(interface{}) error {
+		calls++
+		return ErrInvalidCustomCheck
+	}
+	if err := Email.Validate("someone", check); !errors.Is(err, ErrInvalidEmail) {
+		t.Errorf("Validate with a failing rule = %v, want %v", err, ErrInvalidEmail)
+	}
+	if calls != 0 {
+		t.Errorf("custom check called %d times after the rule failed", calls)
+	}
+	if err := Email.Validate("someone@example.com", check); !errors.Is(err, ErrInvalidCustomCheck) {
+		t.Errorf("Validate with a failing check = %v, want %v", err, ErrInvalidCustomCheck)
+	}
+}
+
+func TestRegisterValidationRule(t *testing.T) {
+	RegisterValidationRule("test_even", func(value, _ string) error {
+		if len(value)%2 != 0 {
+			return errors.New("odd length")
+		}
+		return nil
+	})
+	if err := ValidationRule("test_even").Validate("ab", nil); err != nil {
+		t.Errorf("test_even.Validate(%q) = %v, want nil", "ab", err)
+	}
+	err := ValidationRule("test_even").Validate("abc", nil)
+	if !errors.Is(err, NewFormError("test_even", "")) {
+		t.Errorf("test_even.Validate(%q) = %v, want an error under the rule name", "abc", err)
+	}
+	if err == nil || err.Error() != "odd length" {
+		t.Errorf("test_even.Validate(%q) message = %v, want %q", "abc", err, "odd length")
+	}
+}
+
+func TestRegisterValidationAlias(t *testing.T) {
+	RegisterValidationAlias("test_username", "min_len:3", "max_len:8", "regexp:^[a-z0-9_]+$")
+	tests := []struct {
+		value string
+		want  error
+	}{
+		{value: "root_1"},
+		{value: ""},
+		{value: "ab", want: ErrInvalidMinLen},
+		{value: "much_too_long", want: ErrInvalidMaxLen},
+		{value: "Root", want: ErrInvalidRegexp},
+	}
+	for _, tt := range tests {
+		err := ValidationRule("test_username").Validate(tt.value, nil)
+		if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+			t.Errorf("test_username.Validate(%q) = %v, want %v", tt.value, err, tt.want)
+		}
+	}
+	if err := ValidationRule("test_username|ip").Validate("10.0.0.1", nil); err != nil {
+		t.Errorf("test_username|ip.Validate(%q) = %v, want nil", "10.0.0.1", err)
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	if err := ValidateRules("abc", Required, "min_len:2", "max_len:5"); err != nil {
+		t.Errorf("ValidateRules = %v, want nil", err)
+	}
+	err := ValidateRules("", Required, "min_len:2")
+	if !errors.Is(err, ErrRequired) {
+		t.Errorf("ValidateRules on empty value = %v, want %v", err, ErrRequired)
+	}
+	err = ValidateRules("a", "min_len:2", "max_len:0")
+	if !errors.Is(err, ErrInvalidMinLen) || !strings.Contains(err.Error(), "2") {
+		t.Errorf("ValidateRules = %v, want the first failing rule with its parameter", err)
+	}
+}
@@ -3,47 +3,49 @@ package xtui
 import (
 	c "github.com/faelmori/xtui/components"
 	t "github.com/faelmori/xtui/types"
+	w "github.com/faelmori/xtui/wrappers"
 )
 
 type Config struct{ t.Config }
 type FormFields = t.FormFields
 type FormField = t.FormField
-type InputField = *t.InputField
+type InputField = t.InputField
+type FormResult = t.FormResult
 
 func LogViewer(args ...string) error {
-	return t.LogViewer(args...)
+	return w.LogViewer(args...)
 }
-func ShowForm(form Config) (map[string]string, error) {
+func ShowForm(form Config) (*FormResult, error) {
 	return c.ShowForm(form.Config)
 }
 
+// FormFor shows a form editing the struct cfg points to, built from its `xtui` tags, and writes
+// the submitted values back into it.
+func FormFor(cfg interface{}) error {
+	return c.FormFor(cfg)
+}
+
 func NewConfig(title string, fields FormFields) Config {
 	return Config{Config: t.Config{Title: title, Fields: fields}}
 }
-func NewInputField(placeholder string, typ string, value string, required bool, minValue int, maxValue int, err string, validation func(string) error) *FormField {
+
+// NewInputField creates a text field named after its placeholder; set Nm to name it otherwise.
+func NewInputField(placeholder string, typ string, value string, required bool, minValue int, maxValue int, err string, validation func(string) error) *InputField {
 	return &InputField{
-		t.InputField{
-			Ph:  placeholder,
-			Tp:  typ,
-			Val: value,
-			Req: required,
-			Min: minValue,
-			Max: maxValue,
-			Err: err,
-			Vld: validation,
-		},
+		Ph:  placeholder,
+		Tp:  t.FieldType(typ),
+		Val: value,
+		Req: required,
+		Min: minValue,
+		Max: maxValue,
+		Err: err,
+		Vld: validation,
 	}
 }
 func NewFormFields(title string, fields []FormField) FormFields {
-	ffs := make([]t.FormField, len(fields))
-	for i, f := range fields {
-		ffs[i] = f.FormField
-	}
 	return FormFields{
-		t.FormFields{
-			Title:  title,
-			Fields: ffs,
-		},
+		Title:  title,
+		Fields: fields,
 	}
 }
-func NewFormModel(config t.Config) (map[string]string, error) { return c.ShowForm(config) }
+func NewFormModel(config t.Config) (*FormResult, error) { return c.ShowForm(config) }